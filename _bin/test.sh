@@ -8,7 +8,7 @@ go install honnef.co/go/tools/cmd/staticcheck@latest
 staticcheck ./...
 
 # tests & coverage
-go test -coverprofile=_dist/coverage.out -v ./...
+go test -race -coverprofile=_dist/coverage.out -v ./...
 go tool cover -func=_dist/coverage.out
 
 # clean after self
@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/zoomio/tagify/internal/fsutil"
+	"github.com/zoomio/tagify/model"
+)
+
+const (
+	headerETag            = "ETag"
+	headerLastModified    = "Last-Modified"
+	headerIfNoneMatch     = "If-None-Match"
+	headerIfModifiedSince = "If-Modified-Since"
+	headerUserAgent       = "User-Agent"
+
+	defaultTimeout = time.Second * 3
+)
+
+// Entry is the cached contents of a single URL along with its validators
+// and the result of tagifying them with the options of the given fingerprint (see config.Config.Fingerprint).
+type Entry struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Body         []byte `json:"body"`
+
+	// result
+	Fingerprint string                `json:"fingerprint,omitempty"`
+	DocHash     string                `json:"doc_hash,omitempty"`
+	Meta        *model.Meta           `json:"meta,omitempty"`
+	RawTags     map[string]*model.Tag `json:"raw_tags,omitempty"`
+	Tags        []*model.Tag          `json:"tags,omitempty"`
+}
+
+// Result restores the tagging result stored in the entry, nil if there is none for the given fingerprint.
+func (e *Entry) Result(fingerprint string) *model.Result {
+	if e.Meta == nil || fingerprint == "" || e.Fingerprint != fingerprint {
+		return nil
+	}
+	return &model.Result{
+		Meta:    e.Meta,
+		RawTags: e.RawTags,
+		Tags:    e.Tags,
+	}
+}
+
+// SetResult stores given tagging result in the entry along with the fingerprint of its options.
+func (e *Entry) SetResult(fingerprint string, res *model.Result) {
+	e.Fingerprint = fingerprint
+	e.DocHash = res.Meta.DocHash
+	e.Meta = res.Meta
+	e.RawTags = res.RawTags
+	e.Tags = res.Tags
+}
+
+// Response is the outcome of a conditional request.
+type Response struct {
+	// Entry is set when the source has not been modified since it was cached.
+	Entry *Entry
+	// Cached is the previously cached entry, if any, even if the source has been modified since then.
+	Cached *Entry
+	// Body holds contents of the source, either (re)fetched or cached.
+	Body         []byte
+	ETag         string
+	LastModified string
+}
+
+// NotModified tells whether contents were taken from the cached entry.
+func (r *Response) NotModified() bool {
+	return r.Entry != nil
+}
+
+// Cache is an on-disk HTTP cache, which keeps one entry per URL.
+type Cache struct {
+	dir    string
+	client *http.Client
+}
+
+// New creates new instance of Cache, which stores its entries in the given directory
+// and uses given client (or the default one if it is nil) for the HTTP calls.
+func New(dir string, client *http.Client) *Cache {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Cache{
+		dir:    dir,
+		client: client,
+	}
+}
+
+// Get returns cached entry for the given URL or nil if there is none.
+func (c *Cache) Get(url string) (*Entry, error) {
+	bs, err := os.ReadFile(c.path(url))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read cache entry for %q: %w", url, err)
+	}
+	e := &Entry{}
+	if err = json.Unmarshal(bs, e); err != nil {
+		return nil, fmt.Errorf("failed to decode cache entry for %q: %w", url, err)
+	}
+	return e, nil
+}
+
+// Put stores given entry in the cache.
+func (c *Cache) Put(e *Entry) error {
+	if err := os.MkdirAll(c.dir, os.FileMode(0755)); err != nil {
+		return fmt.Errorf("failed to create cache directory %q: %w", c.dir, err)
+	}
+	bs, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry for %q: %w", e.URL, err)
+	}
+	if err = fsutil.WriteFile(c.path(e.URL), bs); err != nil {
+		return fmt.Errorf("failed to store cache entry for %q: %w", e.URL, err)
+	}
+	return nil
+}
+
+// Fetch calls given URL, if there is a cached entry for it,
+// then request is made conditional using validators of the entry.
+func (c *Cache) Fetch(ctx context.Context, url, userAgent string) (*Response, error) {
+	e, err := c.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error in creating request for source=%s: %w", url, err)
+	}
+	if userAgent != "" {
+		req.Header.Set(headerUserAgent, userAgent)
+	}
+	if e != nil {
+		if e.ETag != "" {
+			req.Header.Set(headerIfNoneMatch, e.ETag)
+		}
+		if e.LastModified != "" {
+			req.Header.Set(headerIfModifiedSince, e.LastModified)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling provided source=%s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && e != nil {
+		return &Response{Entry: e, Cached: e, Body: e.Body, ETag: e.ETag, LastModified: e.LastModified}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status of the provided source=%s: %s", url, resp.Status)
+	}
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error in reading provided source=%s: %w", url, err)
+	}
+
+	return &Response{
+		Cached:       e,
+		Body:         bs,
+		ETag:         resp.Header.Get(headerETag),
+		LastModified: resp.Header.Get(headerLastModified),
+	}, nil
+}
+
+func (c *Cache) path(url string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(url))))
+}
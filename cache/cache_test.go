@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const etag = `"v1"`
+
+func newTestServer(hits *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(headerIfNoneMatch) == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		*hits++
+		w.Header().Set(headerETag, etag)
+		w.Header().Set(headerLastModified, "Mon, 02 Jan 2006 15:04:05 GMT")
+		fmt.Fprint(w, "<p>There was a Boy whose name was Jim</p>")
+	}))
+}
+
+func Test_Get_Missing(t *testing.T) {
+	c := New(t.TempDir(), nil)
+	e, err := c.Get("http://example.com")
+	assert.Nil(t, err)
+	assert.Nil(t, e)
+}
+
+func Test_Put_Get(t *testing.T) {
+	c := New(t.TempDir(), nil)
+	err := c.Put(&Entry{
+		URL:  "http://example.com",
+		ETag: etag,
+		Body: []byte("<title>Example</title>"),
+	})
+	assert.Nil(t, err)
+
+	e, err := c.Get("http://example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, etag, e.ETag)
+	assert.Equal(t, "<title>Example</title>", string(e.Body))
+}
+
+func Test_Fetch_Conditional(t *testing.T) {
+	var hits int
+	srv := newTestServer(&hits)
+	defer srv.Close()
+
+	c := New(t.TempDir(), nil)
+
+	// nothing is cached yet
+	resp, err := c.Fetch(context.TODO(), srv.URL, "")
+	assert.Nil(t, err)
+	assert.False(t, resp.NotModified())
+	assert.Equal(t, etag, resp.ETag)
+	assert.Equal(t, "Mon, 02 Jan 2006 15:04:05 GMT", resp.LastModified)
+	assert.Equal(t, "<p>There was a Boy whose name was Jim</p>", string(resp.Body))
+
+	err = c.Put(&Entry{URL: srv.URL, ETag: resp.ETag, LastModified: resp.LastModified, Body: resp.Body})
+	assert.Nil(t, err)
+
+	// cached now, so server replies with 304
+	resp, err = c.Fetch(context.TODO(), srv.URL, "")
+	assert.Nil(t, err)
+	assert.True(t, resp.NotModified())
+	assert.Equal(t, "<p>There was a Boy whose name was Jim</p>", string(resp.Body))
+	assert.Equal(t, 1, hits)
+}
+
+func Test_Fetch_BadStatus(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := New(t.TempDir(), nil).Fetch(context.TODO(), srv.URL, "")
+	assert.NotNil(t, err)
+}
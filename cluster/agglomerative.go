@@ -0,0 +1,69 @@
+package cluster
+
+import (
+	"github.com/zoomio/tagify/model"
+)
+
+// Agglomerative starts with every result in its own cluster and merges
+// the most similar clusters while their average cosine similarity
+// (i.e. average linkage) is at least the threshold.
+func Agglomerative(results []*model.Result, threshold float64, opts ...Option) []*Cluster {
+	o := newOptions(opts)
+	vs := vectors(results)
+	n := len(vs)
+
+	members := make([][]int, n)
+	sim := make([][]float64, n)
+	for i := range vs {
+		members[i] = []int{i}
+		sim[i] = make([]float64, n)
+		for j := 0; j < i; j++ {
+			sim[i][j] = vs[i].Dot(vs[j])
+			sim[j][i] = sim[i][j]
+		}
+	}
+
+	alive := make([]bool, n)
+	for i := range alive {
+		alive[i] = true
+	}
+
+	for {
+		a, b, best := -1, -1, threshold
+		for i := 0; i < n; i++ {
+			if !alive[i] {
+				continue
+			}
+			for j := i + 1; j < n; j++ {
+				if alive[j] && sim[i][j] >= best && (a < 0 || sim[i][j] > best) {
+					a, b, best = i, j, sim[i][j]
+				}
+			}
+		}
+		if a < 0 {
+			break
+		}
+
+		// merge b into a, with the Lance-Williams update for the average linkage
+		na, nb := float64(len(members[a])), float64(len(members[b]))
+		for c := 0; c < n; c++ {
+			if !alive[c] || c == a || c == b {
+				continue
+			}
+			s := (na*sim[a][c] + nb*sim[b][c]) / (na + nb)
+			sim[a][c], sim[c][a] = s, s
+		}
+		members[a] = append(members[a], members[b]...)
+		members[b] = nil
+		alive[b] = false
+	}
+
+	clusters := []*Cluster{}
+	for i, m := range members {
+		if alive[i] {
+			clusters = append(clusters, newCluster(m, vs, o.labels))
+		}
+	}
+	sortClusters(clusters)
+	return clusters
+}
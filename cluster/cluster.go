@@ -0,0 +1,133 @@
+// Package cluster groups results of Tagify by topic, using vectors
+// of their tags (see similarity.NewVector).
+//
+// KMeans splits results into the given number of clusters,
+// Agglomerative merges results while they are similar enough.
+// Every cluster is labeled with its top aggregated tags.
+package cluster
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/zoomio/tagify/model"
+	"github.com/zoomio/tagify/similarity"
+)
+
+const (
+	defaultLabels     = 5
+	defaultIterations = 100
+	defaultSeed       = 1
+)
+
+// Cluster is a group of similar results.
+type Cluster struct {
+	// Members are indexes of the clustered results.
+	Members []int
+	// Labels are top aggregated tags of the members, where Score is the average
+	// (normalized) score of the tag, Docs is the number of members with the tag
+	// and DocsCount is the number of members.
+	Labels []*model.Tag
+	// Centroid is the average (normalized) vector of the members.
+	Centroid similarity.Vector
+}
+
+// Len returns number of the results in the cluster.
+func (c *Cluster) Len() int {
+	return len(c.Members)
+}
+
+// LabelsStrings returns values of the labels.
+func (c *Cluster) LabelsStrings() []string {
+	return model.ToStrings(c.Labels)
+}
+
+// Option allows to customise clustering.
+type Option func(*options)
+
+type options struct {
+	labels     int
+	iterations int
+	seed       int64
+}
+
+// Labels sets number of labels of every cluster (5 by default).
+func Labels(v int) Option {
+	return func(o *options) {
+		o.labels = v
+	}
+}
+
+// Iterations sets maximum number of k-means iterations (100 by default).
+func Iterations(v int) Option {
+	return func(o *options) {
+		o.iterations = v
+	}
+}
+
+// Seed sets seed of the random initialization of k-means,
+// same seed gives same clusters for the same results.
+func Seed(v int64) Option {
+	return func(o *options) {
+		o.seed = v
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{labels: defaultLabels, iterations: defaultIterations, seed: defaultSeed}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func vectors(results []*model.Result) []similarity.Vector {
+	vs := make([]similarity.Vector, len(results))
+	for i, res := range results {
+		vs[i] = similarity.NewVector(res).Normalize()
+	}
+	return vs
+}
+
+// newCluster computes centroid & labels of the given members.
+func newCluster(members []int, vs []similarity.Vector, labels int) *Cluster {
+	sort.Ints(members)
+
+	sum := similarity.Vector{}
+	docs := map[string]int{}
+	for _, i := range members {
+		for k, w := range vs[i] {
+			sum[k] += w
+			docs[k]++
+		}
+	}
+
+	tags := make([]*model.Tag, 0, len(sum))
+	centroid := make(similarity.Vector, len(sum))
+	for k, w := range sum {
+		avg := w / float64(len(members))
+		centroid[k] = avg
+		tags = append(tags, &model.Tag{Value: k, Score: avg, Docs: docs[k], DocsCount: len(members)})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Score != tags[j].Score {
+			return tags[i].Score > tags[j].Score
+		}
+		return strings.Compare(tags[i].Value, tags[j].Value) < 0
+	})
+	if len(tags) > labels {
+		tags = tags[:labels]
+	}
+
+	return &Cluster{Members: members, Labels: tags, Centroid: centroid}
+}
+
+// sortClusters puts bigger clusters first.
+func sortClusters(cs []*Cluster) {
+	sort.Slice(cs, func(i, j int) bool {
+		if len(cs[i].Members) != len(cs[j].Members) {
+			return len(cs[i].Members) > len(cs[j].Members)
+		}
+		return cs[i].Members[0] < cs[j].Members[0]
+	})
+}
@@ -0,0 +1,71 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/internal/testutil"
+	"github.com/zoomio/tagify/model"
+)
+
+// tickets about two topics: billing (0, 2, 4) & login (1, 3, 5)
+var tickets = []*model.Result{
+	testutil.NewResult(map[string]float64{"invoice": 3, "payment": 2, "card": 1}),
+	testutil.NewResult(map[string]float64{"login": 3, "password": 2, "reset": 1}),
+	testutil.NewResult(map[string]float64{"payment": 3, "refund": 2, "invoice": 1}),
+	testutil.NewResult(map[string]float64{"password": 3, "login": 2, "account": 1}),
+	testutil.NewResult(map[string]float64{"card": 3, "payment": 2, "declined": 1}),
+	testutil.NewResult(map[string]float64{"reset": 3, "password": 2, "email": 1}),
+}
+
+func members(cs []*Cluster) [][]int {
+	out := make([][]int, len(cs))
+	for i, c := range cs {
+		out[i] = c.Members
+	}
+	return out
+}
+
+func Test_KMeans(t *testing.T) {
+	cs := KMeans(tickets, 2, Labels(2))
+	assert.ElementsMatch(t, [][]int{{0, 2, 4}, {1, 3, 5}}, members(cs))
+	for _, c := range cs {
+		assert.Len(t, c.Labels, 2)
+		if c.Members[0] == 0 {
+			assert.Equal(t, "payment", c.Labels[0].Value)
+			assert.Equal(t, 3, c.Labels[0].Docs)
+			assert.Equal(t, 3, c.Labels[0].DocsCount)
+		} else {
+			assert.Equal(t, "password", c.Labels[0].Value)
+		}
+	}
+
+	// same seed, same clusters
+	assert.Equal(t, members(cs), members(KMeans(tickets, 2, Labels(2))))
+
+	// k is capped by the number of results, results without tags are kept apart
+	cs = KMeans(append([]*model.Result{model.EmptyResult()}, tickets[:2]...), 5)
+	assert.ElementsMatch(t, [][]int{{0}, {1}, {2}}, members(cs))
+
+	assert.Empty(t, KMeans(nil, 3))
+}
+
+func Test_Agglomerative(t *testing.T) {
+	cs := Agglomerative(tickets, 0.3, Labels(1))
+	assert.ElementsMatch(t, [][]int{{0, 2, 4}, {1, 3, 5}}, members(cs))
+	for _, c := range cs {
+		assert.Len(t, c.LabelsStrings(), 1)
+	}
+
+	// nothing is similar enough
+	cs = Agglomerative(tickets, 0.99)
+	assert.Len(t, cs, len(tickets))
+
+	// everything is merged
+	cs = Agglomerative(tickets, 0)
+	assert.Len(t, cs, 1)
+	assert.Equal(t, 6, cs[0].Len())
+
+	assert.Empty(t, Agglomerative(nil, 0.5))
+}
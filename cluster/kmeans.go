@@ -0,0 +1,154 @@
+package cluster
+
+import (
+	"math/rand"
+
+	"github.com/zoomio/tagify/model"
+	"github.com/zoomio/tagify/similarity"
+)
+
+// KMeans splits results into at most k clusters using spherical k-means
+// (i.e. the cosine similarity) with the k-means++ initialization,
+// results without tags form their own cluster.
+func KMeans(results []*model.Result, k int, opts ...Option) []*Cluster {
+	o := newOptions(opts)
+	vs := vectors(results)
+
+	// results without tags can't be compared with anything
+	var points, empty []int
+	for i, v := range vs {
+		if len(v) == 0 {
+			empty = append(empty, i)
+		} else {
+			points = append(points, i)
+		}
+	}
+	if k > len(points) {
+		k = len(points)
+	}
+
+	clusters := []*Cluster{}
+	if k > 0 {
+		assignment := kmeans(vs, points, k, o)
+		groups := make([][]int, k)
+		for n, i := range points {
+			groups[assignment[n]] = append(groups[assignment[n]], i)
+		}
+		for _, g := range groups {
+			if len(g) > 0 {
+				clusters = append(clusters, newCluster(g, vs, o.labels))
+			}
+		}
+	}
+	if len(empty) > 0 {
+		clusters = append(clusters, newCluster(empty, vs, o.labels))
+	}
+
+	sortClusters(clusters)
+	return clusters
+}
+
+// kmeans returns index of the centroid for every point.
+func kmeans(vs []similarity.Vector, points []int, k int, o *options) []int {
+	rnd := rand.New(rand.NewSource(o.seed))
+	centroids := initCentroids(vs, points, k, rnd)
+
+	assignment := make([]int, len(points))
+	for i := range assignment {
+		assignment[i] = -1
+	}
+
+	for it := 0; it < o.iterations; it++ {
+		changed := false
+		for n, i := range points {
+			best, bestSim := 0, -1.0
+			for c, centroid := range centroids {
+				if sim := vs[i].Dot(centroid); sim > bestSim {
+					best, bestSim = c, sim
+				}
+			}
+			if assignment[n] != best {
+				assignment[n] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		// move centroids to the mean of their points
+		sums := make([]similarity.Vector, k)
+		for c := range sums {
+			sums[c] = similarity.Vector{}
+		}
+		for n, i := range points {
+			for t, w := range vs[i] {
+				sums[assignment[n]][t] += w
+			}
+		}
+		for c, sum := range sums {
+			// empty cluster keeps its centroid
+			if len(sum) > 0 {
+				centroids[c] = sum.Normalize()
+			}
+		}
+	}
+
+	return assignment
+}
+
+// initCentroids picks k distinct points with the k-means++,
+// i.e. the less similar point is to the chosen centroids, the more likely it is picked next.
+func initCentroids(vs []similarity.Vector, points []int, k int, rnd *rand.Rand) []similarity.Vector {
+	centroids := make([]similarity.Vector, 0, k)
+	chosen := make(map[int]bool, k)
+
+	first := points[rnd.Intn(len(points))]
+	centroids = append(centroids, vs[first])
+	chosen[first] = true
+
+	dist := make([]float64, len(points))
+	for len(centroids) < k {
+		var total float64
+		for n, i := range points {
+			if chosen[i] {
+				dist[n] = 0
+				continue
+			}
+			// cosine distance to the closest centroid
+			d := 1.0
+			for _, c := range centroids {
+				if dd := 1 - vs[i].Dot(c); dd < d {
+					d = dd
+				}
+			}
+			dist[n] = d * d
+			total += dist[n]
+		}
+
+		next := -1
+		if total > 0 {
+			r := rnd.Float64() * total
+			for n := range points {
+				r -= dist[n]
+				if r <= 0 && dist[n] > 0 {
+					next = points[n]
+					break
+				}
+			}
+		}
+		// all the remaining points are identical to the centroids, or rounding errors
+		if next < 0 {
+			for _, i := range points {
+				if !chosen[i] {
+					next = i
+					break
+				}
+			}
+		}
+		centroids = append(centroids, vs[next])
+		chosen[next] = true
+	}
+
+	return centroids
+}
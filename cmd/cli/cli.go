@@ -2,23 +2,28 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"runtime/pprof"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/zoomio/tagify"
 	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/extension"
+	"github.com/zoomio/tagify/extension/wasm"
 )
 
 var (
 	version = "tip"
 
-	source = flag.String("s", "", "source, could be URL (e.g. http://... and https://...) or file path")
-	lang   = flag.String("lang", "", "language of the source, e.g. \"en\"")
+	source       = flag.String("s", "", "source, could be URL (e.g. http://... and https://...) or file path")
+	lang         = flag.String("lang", "", "language of the source, e.g. \"en\"")
+	sectionLangs = flag.Bool("section-lang", false, "detects languages per section (e.g. paragraph), each section uses stop words & segmenter of its language")
 
 	// headless
 	query = flag.String("q", "", "DOM CSS query, e.g. `-q p` will fetch contents of all <p> tags from the given source")
@@ -27,19 +32,67 @@ var (
 	img   = flag.String("i", "", "enables capturing screenshot in the provided path")
 	ua    = flag.String("ua", "", "provide a custom user agent for headless HTTP calls")
 
+	// caching
+	cacheDir = flag.String("cache", "", "directory of the HTTP cache, unchanged web pages are not re-fetched nor re-processed")
+
+	// safeguards
+	blockPrivate = flag.Bool("block-private", false, "prohibits calls to private, loopback & link-local network addresses")
+	schemes      = flag.String("schemes", "", "comma separated list of allowed URL schemes, e.g. \"https,http\"")
+	ports        = flag.String("ports", "", "comma separated list of allowed URL ports, e.g. \"80,443\"")
+	maxSize      = flag.Int64("max-size", 0, "maximum size of the HTTP response in bytes")
+	maxRedirects = flag.Int("max-redirects", -1, "maximum amount of HTTP redirects to follow, 0 disables redirects, negative keeps the default limit of the HTTP client")
+
 	limit       = flag.Int("l", 5, "number of tags to return")
 	verbose     = flag.Bool("v", false, "enables verbose mode")
 	contentType = flag.String("t", tagify.Unknown.String(), fmt.Sprintf("content type of the source, allowed values: %s", strings.Join(config.ContentTypes[:], ", ")))
 	noStopWords = flag.Bool("no-stop", true, "removes stop-words from results (see https://github.com/zoomio/stopwords)")
 	contentOnly = flag.Bool("content", true, "tagify only content")
 
+	// segmentation
+	userDicts  = flag.String("dict", "", "comma separated user dictionaries of the segmenter for Chinese, Japanese, Korean, Thai, Lao, Khmer & Burmese and of the German & Dutch compounds, a word per line optionally followed by its frequency and part of speech")
+	decompound = flag.Float64("decompound", 0.5, "splits German & Dutch compounds into parts, which follow the compounds with the given multiplier of their weights")
+
+	// normalization
+	skipNormalize = flag.Bool("skip-normalize", false, "disables the Unicode normalization & script-specific cleanup of the tokens")
+	foldAccents   = flag.String("fold-accents", "", "comma separated languages, which tokens are folded into the letters without accents, e.g. \"fr,es\", \"*\" for any language")
+
+	// large inputs
+	stream   = flag.Bool("stream", false, "processes plain text line by line with bounded memory, e.g. for large logs & transcripts")
+	maxInput = flag.Int64("max-input", 0, "maximum size of the input to process in bytes")
+
+	// keywords
+	entities  = flag.Bool("entities", false, "keeps names, acronyms & quoted titles as tags with the original casing")
+	algorithm = flag.String("algo", config.Frequency.String(), fmt.Sprintf("keyword extraction algorithm, allowed values: %s", strings.Join(config.Algorithms[:], ", ")))
+
+	// diversification
+	mmr = flag.Float64("mmr", 1, "re-ranks tags with Maximal Marginal Relevance, lambda from 0.0 (diversity) to 1.0 (relevance)")
+
+	// token preservation
+	preserve         = flag.String("preserve", "", fmt.Sprintf("comma separated kinds of the tokens to keep as is, allowed values: all, %s", strings.Join(config.TokenRules[:], ", ")))
+	preservePatterns = flag.String("preserve-patterns", "", "comma separated regular expressions of the tokens to keep as is")
+
+	// near-duplicates
+	signatures = flag.Bool("signatures", false, "computes SimHash & MinHash signatures of the source, SimHash is printed in verbose mode")
+
 	// weighing
 	tagWeights          = flag.String("tag-weights", "", "string with the custom tag weights for HTML & Markdown tagging in the form of <tag1>:<score1>|<tag2>:<score2>")
 	tagWeightsJSON      = flag.String("tag-weights-json", "", "JSON file with the custom tag weights for HTML & Markdown tagging in the form of { \"<tag1>\": <score1>, \"<tag2>\": <score2> }")
+	position            = flag.String("position", "", "weighs tokens by position of their sentences in the form of <kind>:<first>:<boost>[:<span>], kind is one of: step, linear, exp, e.g. \"exp:3:2\"")
 	adjustScores        = flag.Bool("adjust-scores", false, "adjusts tags score to the interval 0.0 to 1.0")
 	extraTagWeights     = flag.String("extra-tag-weights", "", "string with the additional tag weights for HTML & Markdown tagging in the form of <tag1>:<score1>|<tag2>:<score2>")
 	extraTagWeightsJSON = flag.String("extra-tag-weights-json", "", "JSON file with the additional tag weights for HTML & Markdown tagging in the form of { \"<tag1>\": <score1>, \"<tag2>\": <score2> }")
 
+	// extensions
+	exts         = flag.String("ext", "", "comma separated names of the installed extensions to enable (see \"tagify ext list\")")
+	extsManifest = flag.String("ext-manifest", "", "path to the manifest of installed extensions, default is $HOME/.tagify/extensions.json")
+	wasmExts     = flag.String("wasm", "", "comma separated paths to the WebAssembly extension modules (*.wasm) to enable")
+	wasmMemory   = flag.Uint("wasm-memory", wasm.DefaultMemoryPages, "maximum memory of every WebAssembly extension module in 64 KiB pages")
+	wasmTimeout  = flag.Duration("wasm-timeout", wasm.DefaultTimeout, "maximum duration of every call to WebAssembly extension module")
+
+	// configuration file
+	configFile = flag.String("config", os.Getenv("TAGIFY_CONFIG"), "configuration file (YAML, TOML or JSON), default is $HOME/.tagify/config.{yaml,yml,toml,json}")
+	profile    = flag.String("profile", os.Getenv("TAGIFY_PROFILE"), "named profile of the configuration file, e.g. \"news\"")
+
 	// EXPERIMENTAL
 	fullSite = flag.Bool("site", false, "[EXPERIMENTAL] might not be included in next releases: allows to tagify full site (HTML only)")
 
@@ -49,6 +102,16 @@ var (
 )
 
 func main() {
+	// manage extensions
+	if len(os.Args) > 1 && os.Args[1] == "ext" {
+		os.Exit(runExt(os.Args[2:]))
+	}
+
+	// compare tags of two sources
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		os.Exit(runDiff(os.Args[2:]))
+	}
+
 	flag.Parse()
 
 	if *ver {
@@ -70,9 +133,33 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
-	options := []tagify.Option{
-		tagify.TargetType(tagify.ContentTypeOf(*contentType)),
-		tagify.Limit(*limit),
+	prof, err := loadProfile(*configFile, *profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	// defaults of the flags are overridden by the profile, which is overridden by the set flags
+	set := setFlags()
+	options := []tagify.Option{}
+	if !set["t"] {
+		options = append(options, tagify.TargetTypeString(*contentType))
+	}
+	if !set["l"] {
+		options = append(options, tagify.Limit(*limit))
+	}
+	if !set["no-stop"] && *noStopWords {
+		options = append(options, tagify.NoStopWords(*noStopWords))
+	}
+	if !set["content"] && *contentOnly {
+		options = append(options, tagify.ContentOnly(*contentOnly))
+	}
+	options = append(options, prof.Options()...)
+	if set["t"] {
+		options = append(options, tagify.TargetTypeString(*contentType))
+	}
+	if set["l"] {
+		options = append(options, tagify.Limit(*limit))
 	}
 	if *source != "" {
 		options = append(options, tagify.Source(*source))
@@ -80,6 +167,9 @@ func main() {
 	if *lang != "" {
 		options = append(options, tagify.Language(*lang))
 	}
+	if *sectionLangs {
+		options = append(options, tagify.SectionLangs(*sectionLangs))
+	}
 
 	// headless
 	if len(*query) > 0 {
@@ -98,18 +188,84 @@ func main() {
 		options = append(options, tagify.UserAgent(*ua))
 	}
 
+	// caching
+	if *cacheDir != "" {
+		options = append(options, tagify.Cache(*cacheDir))
+	}
+
+	// safeguards
+	if *blockPrivate {
+		options = append(options, tagify.BlockPrivateNetworks(*blockPrivate))
+	}
+	if *schemes != "" {
+		options = append(options, tagify.AllowedSchemes(strings.Split(*schemes, ",")))
+	}
+	if *ports != "" {
+		ps, err := parsePorts(*ports)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		options = append(options, tagify.AllowedPorts(ps))
+	}
+	if *maxSize > 0 {
+		options = append(options, tagify.MaxResponseSize(*maxSize))
+	}
+	if *maxRedirects >= 0 {
+		options = append(options, tagify.MaxRedirects(*maxRedirects))
+	}
+
 	if *verbose {
 		options = append(options, tagify.Verbose(*verbose))
 	}
-	if *noStopWords {
+	if set["no-stop"] {
 		options = append(options, tagify.NoStopWords(*noStopWords))
 	}
-	if *contentOnly {
+	if set["content"] {
 		options = append(options, tagify.ContentOnly(*contentOnly))
 	}
 	if *fullSite {
 		options = append(options, tagify.FullSite(*fullSite))
 	}
+	if *userDicts != "" {
+		options = append(options, tagify.UserDict(strings.Split(*userDicts, ",")...))
+	}
+	if set["decompound"] {
+		options = append(options, tagify.Decompound(*decompound))
+	}
+	if *skipNormalize {
+		options = append(options, tagify.SkipNormalize(*skipNormalize))
+	}
+	if *foldAccents != "" {
+		options = append(options, tagify.FoldAccents(strings.Split(*foldAccents, ",")...))
+	}
+	if *stream {
+		options = append(options, tagify.Stream(*stream))
+	}
+	if *maxInput > 0 {
+		options = append(options, tagify.MaxInputSize(*maxInput))
+	}
+	if *preserve != "" {
+		options = append(options, tagify.PreserveString(*preserve))
+	}
+	if *preservePatterns != "" {
+		options = append(options, tagify.PreservePatterns(strings.Split(*preservePatterns, ",")...))
+	}
+	if *signatures {
+		options = append(options, tagify.Signatures(*signatures))
+	}
+	if set["algo"] {
+		options = append(options, tagify.AlgorithmString(*algorithm))
+	}
+	if *position != "" {
+		options = append(options, tagify.PositionString(*position))
+	}
+	if *entities {
+		options = append(options, tagify.Entities(*entities))
+	}
+	if set["mmr"] {
+		options = append(options, tagify.Diversify(*mmr))
+	}
 	if *tagWeights != "" {
 		options = append(options, tagify.TagWeightsString(*tagWeights))
 	} else if *tagWeightsJSON != "" {
@@ -124,6 +280,31 @@ func main() {
 		options = append(options, tagify.ExtraTagWeightsJSON(*extraTagWeightsJSON))
 	}
 
+	extNames := *exts
+	if extNames == "" {
+		extNames = strings.Join(prof.Extensions, ",")
+	}
+	extensions := []extension.Extension{}
+	if extNames != "" {
+		loaded, err := loadExts(*extsManifest, extNames)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		extensions = append(extensions, loaded...)
+	}
+	if *wasmExts != "" {
+		loaded, err := loadWasmExts(*wasmExts, wasm.Limits{MemoryPages: uint32(*wasmMemory), Timeout: *wasmTimeout})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		extensions = append(extensions, loaded...)
+	}
+	if len(extensions) > 0 {
+		options = append(options, tagify.Extensions(extensions))
+	}
+
 	// print progress spinner to terminal
 	stopCh := make(chan struct{})
 	var wg sync.WaitGroup
@@ -136,7 +317,8 @@ func main() {
 	close(stopCh)
 	wg.Wait()
 	if err != nil {
-		if *verbose {
+		var cfgErr *config.ValidationError
+		if *verbose || errors.As(err, &cfgErr) {
 			fmt.Fprintf(os.Stderr, "failed to get tags: %v\n", err)
 		}
 		os.Exit(2)
@@ -159,6 +341,9 @@ func main() {
 		fmt.Printf("title: %s\n", res.Meta.DocTitle)
 		fmt.Printf("hash: %s\n", res.Meta.DocHash)
 		fmt.Printf("content-type: %s\n", res.Meta.ContentType)
+		if *signatures {
+			fmt.Printf("simhash: %016x\n", res.Meta.SimHash)
+		}
 		println()
 	}
 
@@ -170,6 +355,18 @@ func main() {
 	fmt.Fprintf(os.Stdout, "%s%s\n", prfx, strings.Join(res.TagsStrings(), " "))
 }
 
+func parsePorts(s string) ([]int, error) {
+	var ps []int
+	for _, v := range strings.Split(s, ",") {
+		p, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("wrong port %q: %w", v, err)
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
 func shellSpinner(stopCh chan struct{}, wg *sync.WaitGroup) {
 	ticker := time.NewTicker(80 * time.Millisecond)
 	i := -1
@@ -188,3 +385,24 @@ func shellSpinner(stopCh chan struct{}, wg *sync.WaitGroup) {
 		}
 	}
 }
+
+// loadProfile reads the named profile from the given configuration file or from the default one if it exists,
+// environment overrides are applied in any case.
+func loadProfile(path, name string) (*config.Profile, error) {
+	if path == "" {
+		var err error
+		if path, err = config.DefaultFilePath(); err != nil {
+			return nil, err
+		}
+	}
+	return config.LoadProfile(path, name)
+}
+
+// setFlags returns names of the flags, which have been set explicitly.
+func setFlags() map[string]bool {
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	return set
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/zoomio/tagify"
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/diff"
+	"github.com/zoomio/tagify/model"
+)
+
+const diffUsage = `usage: tagify diff -a <source> -b <source> [flags]
+
+compares tags of two sources (e.g. two versions of the same page):
+tags gained, lost & with significant changes of the weight, along with the similarity.
+`
+
+// runDiff handles "tagify diff ..." command, returns exit code.
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, diffUsage)
+		fs.PrintDefaults()
+	}
+	a := fs.String("a", "", "first source (e.g. before), could be URL or file path")
+	b := fs.String("b", "", "second source (e.g. after), could be URL or file path")
+	limit := fs.Int("l", 20, "number of tags of every source & of the changes of every kind to show, all the found tags are compared")
+	contentType := fs.String("t", tagify.Unknown.String(), fmt.Sprintf("content type of the sources, allowed values: %s", strings.Join(config.ContentTypes[:], ", ")))
+	lang := fs.String("lang", "", "language of the sources, e.g. \"en\"")
+	noStopWords := fs.Bool("no-stop", true, "removes stop-words from results")
+	contentOnly := fs.Bool("content", true, "tagify only content")
+	threshold := fs.Float64("threshold", diff.DefaultThreshold, "minimal change of the tag weight (0.0 to 1.0) to be reported")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if *a == "" || *b == "" {
+		fs.Usage()
+		return 1
+	}
+
+	options := []tagify.Option{
+		tagify.TargetTypeString(*contentType),
+		tagify.Limit(*limit),
+		tagify.NoStopWords(*noStopWords),
+		tagify.ContentOnly(*contentOnly),
+	}
+	if *lang != "" {
+		options = append(options, tagify.Language(*lang))
+	}
+
+	tagger, err := tagify.NewTagger(options...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	resA, err := tagger.Tag(ctx, *a)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get tags of %s: %v\n", *a, err)
+		return 2
+	}
+	resB, err := tagger.Tag(ctx, *b)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to get tags of %s: %v\n", *b, err)
+		return 2
+	}
+
+	printDiff(diff.Compare(resA, resB, diff.Threshold(*threshold)), resA, resB, *limit)
+	return 0
+}
+
+// printDiff prints ranked tags of the sources & at most limit changes of every kind.
+func printDiff(r *diff.Report, a, b *model.Result, limit int) {
+	fmt.Printf("similarity: %.2f\n", r.Similarity)
+	fmt.Printf("a: %s\n", strings.Join(a.TagsStrings(), " "))
+	fmt.Printf("b: %s\n", strings.Join(b.TagsStrings(), " "))
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHANGE\tTAG\tA\tB\tDELTA")
+	printChanges := func(kind string, cs []*diff.Change) {
+		if limit > 0 && len(cs) > limit {
+			cs = cs[:limit]
+		}
+		for _, c := range cs {
+			fmt.Fprintf(w, "%s\t%s\t%.2f\t%.2f\t%+.2f\n", kind, c.Value, c.Before, c.After, c.Delta)
+		}
+	}
+	printChanges("gained", r.Gained)
+	printChanges("lost", r.Lost)
+	printChanges("changed", r.Changed)
+	w.Flush()
+
+	fmt.Printf("unchanged: %d\n", r.Unchanged)
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/zoomio/tagify/extension"
+	"github.com/zoomio/tagify/extension/wasm"
+	"github.com/zoomio/tagify/processor"
+)
+
+const extUsage = `usage: tagify ext <command> [flags] [name]
+
+commands:
+  install -src <source> -sha256 <checksum> <name>  downloads, verifies & installs extension App
+  list                                             lists installed extensions
+  update -sha256 <checksum> <name>                 re-installs extension App from its source into its directory
+  remove <name>                                    removes installed extension App
+  info <name>                                      prints details of the installed extension App
+
+install & update require either SHA-256 checksum (-sha256) or ed25519 signature (-sig),
+which is verified with the trusted keys (-keys), use -insecure to skip verification.
+`
+
+// runExt handles "tagify ext ..." commands, returns exit code.
+func runExt(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, extUsage)
+		return 1
+	}
+
+	cmd := args[0]
+	fs := flag.NewFlagSet("ext "+cmd, flag.ContinueOnError)
+	manifestPath := fs.String("manifest", "", "path to the manifest of installed extensions, default is $HOME/.tagify/extensions.json")
+	dir := fs.String("dir", "", "directory to install extensions into, default is $HOME/bin or the current directory of the updated extension")
+	src := fs.String("src", "", "source of the extension App, could be URL or file path")
+	checksum := fs.String("sha256", "", "expected SHA-256 checksum (hex) of the extension App")
+	sig := fs.String("sig", "", "base64 encoded ed25519 signature of the extension App")
+	keys := fs.String("keys", "", "file with base64 encoded ed25519 trusted public keys (one per line), default is $HOME/.tagify/trusted_keys")
+	insecure := fs.Bool("insecure", false, "allows to install extension App without checksum")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 1
+	}
+
+	m, err := loadManifest(*manifestPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 3
+	}
+
+	ctx := context.Background()
+	name := fs.Arg(0)
+
+	newApp := func(name, src string) (*extension.App, error) {
+		app := extension.New(name, src)
+		app.SetChecksum(*checksum)
+		app.RequireChecksum(!*insecure && *sig == "")
+		if *sig != "" {
+			bs, err := extension.ParseSignature(*sig)
+			if err != nil {
+				return nil, err
+			}
+			app.SetSignature(bs)
+		}
+		if *insecure && *sig == "" {
+			return app, nil
+		}
+		trusted, err := loadTrustedKeys(*keys)
+		if err != nil {
+			return nil, err
+		}
+		app.SetTrustedKeys(trusted)
+		return app, nil
+	}
+
+	switch cmd {
+	case "install":
+		if name == "" || *src == "" {
+			fmt.Fprint(os.Stderr, extUsage)
+			return 1
+		}
+		if _, ok := m.Get(name); ok {
+			fmt.Fprintf(os.Stderr, "extension %q is already installed, use \"tagify ext update %s\"\n", name, name)
+			return 1
+		}
+		var app *extension.App
+		if app, err = newApp(name, *src); err == nil {
+			err = installExt(ctx, m, app, *dir)
+		}
+	case "update":
+		e, ok := findExt(m, name)
+		if !ok {
+			return 1
+		}
+		// extension stays where it is, unless asked otherwise
+		installDir := *dir
+		if installDir == "" && e.Path != "" {
+			installDir = filepath.Dir(e.Path)
+		}
+		var app *extension.App
+		if app, err = newApp(e.Name, e.Source); err == nil {
+			err = installExt(ctx, m, app, installDir)
+		}
+	case "remove":
+		e, ok := findExt(m, name)
+		if !ok {
+			return 1
+		}
+		if err = extension.FromManifest(e).Uninstall(); err == nil {
+			m.Remove(name)
+			err = m.Save()
+		}
+	case "info":
+		e, ok := findExt(m, name)
+		if !ok {
+			return 1
+		}
+		fmt.Printf("name:         %s\n", e.Name)
+		fmt.Printf("version:      %s\n", e.Version)
+		fmt.Printf("source:       %s\n", e.Source)
+		fmt.Printf("path:         %s\n", e.Path)
+		fmt.Printf("checksum:     %s\n", e.Checksum)
+		fmt.Printf("installed at: %s\n", e.InstalledAt.Format(time.RFC3339))
+	case "list":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tVERSION\tSOURCE")
+		for _, e := range m.List() {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", e.Name, e.Version, e.Source)
+		}
+		w.Flush()
+	default:
+		fmt.Fprint(os.Stderr, extUsage)
+		return 1
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	}
+	return 0
+}
+
+func installExt(ctx context.Context, m *extension.Manifest, app *extension.App, dir string) error {
+	if dir != "" {
+		app.SetInstallDir(dir)
+	}
+	if err := app.Install(ctx); err != nil {
+		return err
+	}
+	e := app.ManifestEntry()
+	e.InstalledAt = time.Now()
+	m.Put(e)
+	return m.Save()
+}
+
+func findExt(m *extension.Manifest, name string) (*extension.ManifestEntry, bool) {
+	if name == "" {
+		fmt.Fprint(os.Stderr, extUsage)
+		return nil, false
+	}
+	e, ok := m.Get(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "extension %q is not installed\n", name)
+	}
+	return e, ok
+}
+
+func loadManifest(path string) (*extension.Manifest, error) {
+	if path == "" {
+		var err error
+		path, err = extension.DefaultManifestPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return extension.LoadManifest(path)
+}
+
+func loadTrustedKeys(path string) ([]ed25519.PublicKey, error) {
+	if path == "" {
+		var err error
+		path, err = extension.DefaultTrustedKeysPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return extension.LoadTrustedKeys(path)
+}
+
+// loadExts creates extensions out of the installed extension Apps with the given names.
+func loadExts(manifestPath, names string) ([]extension.Extension, error) {
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	exts := []extension.Extension{}
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		e, ok := m.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("extension %q is not installed", name)
+		}
+		app := extension.FromManifest(e)
+		if err = app.Verify(); err != nil {
+			return nil, fmt.Errorf("extension %q has been modified since it was installed: %w", name, err)
+		}
+		exts = append(exts, processor.NewAppExt(app))
+	}
+	return exts, nil
+}
+
+// loadWasmExts instantiates WebAssembly extension modules from the given files,
+// name of the extension is the name of its file without ".wasm".
+func loadWasmExts(files string, limits wasm.Limits) ([]extension.Extension, error) {
+	exts := []extension.Extension{}
+	for _, file := range strings.Split(files, ",") {
+		file = strings.TrimSpace(file)
+		name := strings.TrimSuffix(filepath.Base(file), ".wasm")
+		ext, err := wasm.Load(context.Background(), name, "", file, limits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load WebAssembly extension %q: %w", file, err)
+		}
+		exts = append(exts, ext)
+	}
+	return exts, nil
+}
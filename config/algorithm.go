@@ -0,0 +1,40 @@
+package config
+
+// Keyword extraction algorithms
+const (
+	// Frequency counts weighted tokens and ranks them with TF-IDF.
+	Frequency Algorithm = iota
+	// RAKE is the Rapid Automatic Keyword Extraction, higher scores are better.
+	RAKE
+	// YAKE is the Yet Another Keyword Extractor, lower scores are better.
+	YAKE
+)
+
+var (
+	Algorithms = [...]string{
+		"frequency",
+		"rake",
+		"yake",
+	}
+)
+
+// Algorithm of the keyword extraction.
+type Algorithm byte
+
+// AlgorithmOf returns Algorithm based on string value and tells whether it is known.
+func AlgorithmOf(algorithm string) (Algorithm, bool) {
+	for i, key := range Algorithms {
+		if key == algorithm {
+			return Algorithm(i), true
+		}
+	}
+	return Frequency, false
+}
+
+// String ...
+func (a Algorithm) String() string {
+	if a > YAKE {
+		return "unknown"
+	}
+	return Algorithms[a]
+}
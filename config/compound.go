@@ -0,0 +1,174 @@
+package config
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MinCompoundPart is the minimum number of letters in the parts of the compounds.
+const MinCompoundPart = 3
+
+var (
+	// linking elements between the parts of the compounds (e.g. "s" of "Arbeitsplatz"),
+	// which are also the inflections of the last part
+	compoundLinks = map[string][]string{
+		"de": {"s", "es", "n", "en", "e", "er", "ens"},
+		"nl": {"s", "en", "e", "er"},
+	}
+
+	// endings dropped from the words, which aren't the last parts of the compounds
+	// (e.g. "e" of "Miete" in "Mietvertrag" or "en" of "fahren" in "Fahrzeug")
+	compoundElisions = map[string][]string{
+		"de": {"e", "en"},
+		"nl": {"en"},
+	}
+
+	// decompounders are loaded once per language & set of the user dictionaries,
+	// hence are shared between configurations
+	decompounders = newDictCache[*Decompounder]()
+)
+
+// NeedsDecompounding tells whether compounds of the given language could be split into parts,
+// i.e. German or Dutch.
+func NeedsDecompounding(lang string) bool {
+	_, ok := compoundLinks[lang]
+	return ok
+}
+
+// LoadDecompounder loads decompounder for the given language with its embedded dictionary
+// along with the given user dictionaries (see UserDict), frequencies & parts of speech of the user words are ignored.
+// Decompounder is loaded once per language & set of dictionaries and is shared by all configurations,
+// it is loaded again once any of the dictionaries is modified.
+func LoadDecompounder(lang string, paths ...string) (*Decompounder, error) {
+	return decompounders.load(lang, paths, func() (*Decompounder, error) {
+		d := NewDecompounder(compoundLinks[lang], embeddedWords(lang)...)
+		d.elisions = compoundElisions[lang]
+		for _, p := range paths {
+			err := readDict(p, func(word string, _ float64, _ ...string) error {
+				d.words[strings.ToLower(word)] = true
+				return nil
+			})
+			if err != nil {
+				return nil, &DictError{Path: p, Err: err}
+			}
+		}
+		return d, nil
+	})
+}
+
+// Decompounder splits compounds into the fewest known words, which might be joined by the linking elements
+// or lose their endings, e.g. "datenschutzgrundverordnung" is split into "datenschutz", "grund" & "verordnung"
+// and "mietvertrag" into "miete" & "vertrag". It is safe for concurrent use once created.
+type Decompounder struct {
+	words    map[string]bool
+	links    []string
+	elisions []string
+}
+
+// NewDecompounder creates decompounder for the given linking elements & dictionary words.
+func NewDecompounder(links []string, words ...string) *Decompounder {
+	d := &Decompounder{words: make(map[string]bool, len(words)), links: links}
+	for _, w := range words {
+		d.words[strings.ToLower(w)] = true
+	}
+	return d
+}
+
+type compoundCost struct {
+	parts, links int
+	prev         int
+	part         string
+}
+
+func (c compoundCost) less(o compoundCost) bool {
+	if c.parts != o.parts {
+		return c.parts < o.parts
+	}
+	return c.links < o.links
+}
+
+// Split returns parts of the given lowercase compound, nil if it is a known word
+// or it can't be split into the known words.
+func (d *Decompounder) Split(word string) []string {
+	n := len(word)
+	if utf8.RuneCountInString(word) < 2*MinCompoundPart || strings.IndexFunc(word, isNotLetter) >= 0 {
+		return nil
+	}
+
+	best := make([]*compoundCost, n+1)
+	best[0] = &compoundCost{}
+	relax := func(from, to, links int, part string) {
+		c := compoundCost{parts: best[from].parts + 1, links: best[from].links + links, prev: from, part: part}
+		if best[to] == nil || c.less(*best[to]) {
+			best[to] = &c
+		}
+	}
+	for i := 0; i < n; i++ {
+		if best[i] == nil || !utf8.RuneStart(word[i]) {
+			continue
+		}
+		for j := i + 1; j <= n; j++ {
+			if j < n && !utf8.RuneStart(word[j]) {
+				continue
+			}
+			part := word[i:j]
+			if utf8.RuneCountInString(part) < MinCompoundPart {
+				continue
+			}
+			if d.words[part] {
+				d.relaxLinks(word, j, 0, func(to, links int) { relax(i, to, links, part) })
+			}
+			if j == n {
+				continue
+			}
+			for _, e := range d.elisions {
+				if full := part + e; d.words[full] {
+					d.relaxLinks(word, j, 1, func(to, links int) { relax(i, to, links, full) })
+				}
+			}
+		}
+	}
+
+	if best[n] == nil || best[n].parts < 2 {
+		return nil
+	}
+	parts := make([]string, best[n].parts)
+	for i, k := n, len(parts)-1; i > 0; i, k = best[i].prev, k-1 {
+		parts[k] = best[i].part
+	}
+	return parts
+}
+
+// relaxLinks calls relax for the part ending at the given offset and for the part followed by the linking elements.
+func (d *Decompounder) relaxLinks(word string, end, links int, relax func(to, links int)) {
+	relax(end, links)
+	for _, link := range d.links {
+		if strings.HasPrefix(word[end:], link) {
+			relax(end+len(link), links+1)
+		}
+	}
+}
+
+// Decompounder returns decompounder for the language of the configuration,
+// nil if compounds aren't split (see Decompound & NeedsDecompounding).
+// Decompounder is resolved once per language of the configuration.
+func (c *Config) Decompounder() *Decompounder {
+	if !c.Decompound || !NeedsDecompounding(c.Lang) {
+		return nil
+	}
+	if c.decomp != nil && c.decompLang == c.Lang {
+		return c.decomp
+	}
+	d, err := LoadDecompounder(c.Lang, c.UserDicts...)
+	if err != nil {
+		// broken user dictionaries are reported by Config.Validate
+		d, _ = LoadDecompounder(c.Lang)
+	}
+	c.decomp, c.decompLang = d, c.Lang
+	return d
+}
+
+func isNotLetter(r rune) bool {
+	return !unicode.IsLetter(r)
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Decompounder_Split(t *testing.T) {
+	tests := []struct {
+		name   string
+		lang   string
+		word   string
+		expect []string
+	}{
+		{"fewest parts", "de", "datenschutzgrundverordnung", []string{"datenschutz", "grund", "verordnung"}},
+		{"linking element", "de", "arbeitsplatz", []string{"arbeit", "platz"}},
+		{"inflected last part", "de", "datenschutzbehörden", []string{"datenschutz", "behörde"}},
+		{"several linking elements", "de", "bundesverfassungsgericht", []string{"bund", "verfassung", "gericht"}},
+		{"dropped ending", "de", "mietvertrag", []string{"miete", "vertrag"}},
+		{"dropped infinitive ending", "de", "fahrzeughersteller", []string{"fahrzeug", "hersteller"}},
+		{"fewest linking elements", "de", "krankenversicherung", []string{"kranken", "versicherung"}},
+		{"known word", "de", "datenschutz", nil},
+		{"unknown parts", "de", "datenschutzfoo", nil},
+		{"short parts", "de", "hofbahn", []string{"hof", "bahn"}},
+		{"hyphenated", "de", "daten-schutz", nil},
+		{"dutch", "nl", "arbeidsongeschiktheidsverzekering", []string{"arbeid", "ongeschiktheid", "verzekering"}},
+		{"dutch inflected last part", "nl", "gemeenteraadsverkiezingen", []string{"gemeenteraad", "verkiezing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := LoadDecompounder(tt.lang)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expect, d.Split(tt.word))
+		})
+	}
+}
+
+func Test_Decompounder(t *testing.T) {
+	dict := filepath.Join(t.TempDir(), "legal.txt")
+	assert.Nil(t, os.WriteFile(dict, []byte("# legal terms\nAuftragsverarbeitung 1000 n\n"), 0644))
+
+	assert.Nil(t, New(Language("de")).Decompounder())
+	assert.Nil(t, New(Language("en"), Decompound(0.5)).Decompounder())
+
+	d := New(Language("de"), Decompound(0.5)).Decompounder()
+	assert.NotNil(t, d)
+	assert.Same(t, d, New(Language("de"), Decompound(1)).Decompounder())
+	assert.Equal(t, []string{"auftrag", "verarbeitung", "vertrag"}, d.Split("auftragsverarbeitungsvertrag"))
+
+	d = New(Language("de"), Decompound(0.5), UserDict(dict)).Decompounder()
+	assert.Equal(t, []string{"auftragsverarbeitung", "vertrag"}, d.Split("auftragsverarbeitungsvertrag"))
+}
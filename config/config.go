@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"regexp"
+	"sync"
 	"time"
 
 	"github.com/zoomio/stopwords"
@@ -21,7 +24,16 @@ var (
 		"de": stopwords.Words(stopwords.StopWordsDe),
 		"es": stopwords.Words(stopwords.StopWordsEs),
 		"fr": stopwords.Words(stopwords.StopWordsFr),
+		"nl": stopwords.WordsSlice(extraStopWords["nl"]),
+		"th": stopwords.WordsSlice(extraStopWords["th"]),
+		"lo": stopwords.WordsSlice(extraStopWords["lo"]),
+		"km": stopwords.WordsSlice(extraStopWords["km"]),
+		"my": stopwords.WordsSlice(extraStopWords["my"]),
 	}
+
+	// registers of stop words are immutable, hence are shared between configurations
+	stopWordsMu  sync.Mutex
+	stopWordsReg = map[string]*stopwords.Register{}
 )
 
 // New ...
@@ -38,6 +50,15 @@ func New(options ...Option) *Config {
 	return c
 }
 
+// NewWithError creates configuration and validates it (see Config.Validate).
+func NewWithError(options ...Option) (*Config, error) {
+	c := New(options...)
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 // Config ...
 type Config struct {
 	Source string
@@ -54,14 +75,44 @@ type Config struct {
 	Screenshot bool
 	UserAgent  string
 
+	// caching
+	CacheDir string
+
+	// safeguards
+	BlockPrivateNetworks bool
+	AllowedSchemes       []string
+	AllowedPorts         []int
+	MaxResponseSize      int64
+	MaxRedirects         *int // nil means not set, 0 disables redirects
+
 	// misc
-	Limit       int
-	Verbose     bool
-	NoStopWords bool
-	SkipLang    bool
-	StopWords   *stopwords.Register
-	ContentOnly bool
-	FullSite    bool
+	Limit        int
+	Verbose      bool
+	NoStopWords  bool
+	SkipLang     bool
+	SectionLangs bool // languages are detected per section (e.g. paragraph), language of the document is the fallback
+	StopWords    *stopwords.Register
+	ContentOnly  bool
+	FullSite     bool
+
+	// large inputs
+	Stream       bool  // plain text is processed line by line with bounded memory
+	MaxInputSize int64 // in bytes, 0 means not limited
+
+	// token preservation
+	TokenRules    []TokenRule      // kinds of the tokens to keep as is
+	TokenPatterns []*regexp.Regexp // custom patterns of the tokens to keep as is
+
+	// near-duplicates
+	Signatures bool // SimHash & MinHash signatures of the document
+
+	// keywords
+	Algorithm
+	Entities bool // named entities are kept as tags with the original casing
+
+	// diversification
+	Diversify bool    // re-ranks tags with Maximal Marginal Relevance
+	MMRLambda float64 // trade-off between relevance (1.0) and diversity (0.0)
 
 	// weighing
 	AllTagWeights bool
@@ -69,20 +120,151 @@ type Config struct {
 	ExtraTagWeights TagWeights
 	ExcludeTags     TagWeights
 	AdjustScores    bool
+	PositionDecay   Decay // multiplier of the weights by position of the sentence, nil disables it
 
 	Extensions []extension.Extension
 
-	seg Segmenter
+	// normalization
+	SkipNormalize bool     // tokens are only lowercased, without the Unicode normalization
+	FoldAccents   []string // languages, which tokens are folded into the letters without accents, "*" for any language
+
+	// segmentation
+	UserDicts          []string // user dictionaries of the segmenter (see UserDict)
+	Decompound         bool     // German & Dutch compounds are followed by their parts
+	CompoundPartWeight float64  // multiplier of the weights of the compound parts
+
+	seg        Segmenter
+	decomp     *Decompounder // resolved for the decompLang, see Decompounder
+	decompLang string
+
+	// errors of the options, reported by Validate
+	errs []error
+}
+
+// Clone returns copy of the configuration, which could be modified independently,
+// except for the tag weights & stop words, which are shared and never modified.
+func (c *Config) Clone() *Config {
+	cp := *c
+	// segmenter & decompounder depend on the language & the user dictionaries, which might be changed in the copy
+	cp.seg = nil
+	cp.decomp = nil
+	cp.errs = append([]error(nil), c.errs...)
+	return &cp
+}
+
+// Validate checks configuration, all found problems (including errors of the options)
+// are returned at once with the *ValidationError.
+func (c *Config) Validate() error {
+	errs := append([]error(nil), c.errs...)
+
+	if c.ContentType > Markdown {
+		errs = append(errs, &ContentTypeError{Value: fmt.Sprintf("%d", c.ContentType)})
+	}
+	if c.Algorithm > YAKE {
+		errs = append(errs, &AlgorithmError{Value: fmt.Sprintf("%d", c.Algorithm)})
+	}
+	if _, ok := allStopWords[c.Lang]; c.Lang != "" && !ok {
+		errs = append(errs, &LanguageError{Lang: c.Lang})
+	}
+
+	for _, lang := range c.FoldAccents {
+		if _, ok := allStopWords[lang]; lang != "*" && !ok {
+			errs = append(errs, &LanguageError{Lang: lang})
+		}
+	}
+
+	if c.Limit < 0 {
+		errs = append(errs, &LimitError{Name: "limit", Value: fmt.Sprint(c.Limit)})
+	}
+	if c.Timeout < 0 {
+		errs = append(errs, &LimitError{Name: "timeout", Value: c.Timeout.String()})
+	}
+	if c.WaitUntil < 0 {
+		errs = append(errs, &LimitError{Name: "wait until", Value: c.WaitUntil.String()})
+	}
+	if c.MaxResponseSize < 0 {
+		errs = append(errs, &LimitError{Name: "max response size", Value: fmt.Sprint(c.MaxResponseSize)})
+	}
+	if c.MaxRedirects != nil && *c.MaxRedirects < 0 {
+		errs = append(errs, &LimitError{Name: "max redirects", Value: fmt.Sprint(*c.MaxRedirects)})
+	}
+	if c.MaxInputSize < 0 {
+		errs = append(errs, &LimitError{Name: "max input size", Value: fmt.Sprint(c.MaxInputSize)})
+	}
+	if c.MMRLambda < 0 || c.MMRLambda > 1 {
+		errs = append(errs, &LimitError{Name: "diversity lambda", Value: fmt.Sprint(c.MMRLambda)})
+	}
+	if c.CompoundPartWeight < 0 {
+		errs = append(errs, &LimitError{Name: "compound part weight", Value: fmt.Sprint(c.CompoundPartWeight)})
+	}
+	for _, p := range c.AllowedPorts {
+		if p < 1 || p > 65535 {
+			errs = append(errs, &LimitError{Name: "port", Value: fmt.Sprint(p)})
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errs: errs}
+	}
+	return nil
+}
+
+func (c *Config) readTagWeights(name, v string, readerType TagWeightsType) TagWeights {
+	weights, err := readTagWeights(name, v, readerType)
+	if err != nil {
+		c.errs = append(c.errs, err)
+	}
+	return weights
+}
+
+// IsSupportedLang tells whether the given language is supported, i.e. it has stop words.
+func IsSupportedLang(lang string) bool {
+	_, ok := allStopWords[lang]
+	return ok
+}
+
+// FoldsAccents tells whether tokens of the language of the configuration are folded
+// into the letters without accents (see FoldAccents).
+func (c *Config) FoldsAccents() bool {
+	for _, lang := range c.FoldAccents {
+		if lang == "*" || lang == c.Lang {
+			return true
+		}
+	}
+	return false
 }
 
 // SetStopWords ...
 func (c *Config) SetStopWords(lang string) {
 	c.Lang = lang
-	if found, ok := allStopWords[lang]; ok {
-		c.StopWords = stopwords.Setup(found)
-	} else {
-		c.StopWords = stopwords.Setup(stopwords.Words(stopwords.StopWordsEn))
+	c.StopWords = stopWordsFor(lang)
+}
+
+// LangStopWords returns stop words of the configuration
+// or stop words of its language if there are none.
+func (c *Config) LangStopWords() *stopwords.Register {
+	if c.StopWords != nil {
+		return c.StopWords
+	}
+	return stopWordsFor(c.Lang)
+}
+
+// stopWordsFor returns register of stop words for the given language,
+// registers are created once per language, English is used for unknown languages.
+func stopWordsFor(lang string) *stopwords.Register {
+	found, ok := allStopWords[lang]
+	if !ok {
+		lang = "en"
+		found = allStopWords[lang]
+	}
+	stopWordsMu.Lock()
+	defer stopWordsMu.Unlock()
+	reg, ok := stopWordsReg[lang]
+	if !ok {
+		reg = stopwords.Setup(found)
+		stopWordsReg[lang] = reg
 	}
+	return reg
 }
 
 // Segmenter ...
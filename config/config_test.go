@@ -0,0 +1,100 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	badJSON := filepath.Join(dir, "bad.json")
+	assert.Nil(t, os.WriteFile(badJSON, []byte("{\"h1\": "), 0644))
+	goodJSON := filepath.Join(dir, "good.json")
+	assert.Nil(t, os.WriteFile(goodJSON, []byte("{\"h1\": 2.5}"), 0644))
+
+	tests := []struct {
+		name    string
+		options []Option
+		errs    int
+	}{
+		{"valid", []Option{Language("en"), Limit(5), TagWeightsJSON(goodJSON), TargetTypeString("HTML")}, 0},
+		{"valid token preservation", []Option{PreserveString("hashtags, langs"), PreservePatterns(`v\d+`)}, 0},
+		{"malformed weights", []Option{TagWeightsString("h1:2|h2|p:x")}, 1},
+		{"missing weights file", []Option{ExtraTagWeightsJSON(filepath.Join(dir, "missing.json"))}, 1},
+		{"bad weights file", []Option{TagWeightsJSON(badJSON)}, 1},
+		{"unknown content type", []Option{TargetTypeString("PDF")}, 1},
+		{"unsupported language", []Option{Language("xx")}, 1},
+		{"unknown algorithm", []Option{AlgorithmString("textrank")}, 1},
+		{"malformed decay", []Option{PositionString("exp:first:2")}, 1},
+		{"token preservation", []Option{PreserveString("all,emojis"), PreservePatterns(`v\d+`, `(`)}, 2},
+		{"accent folding", []Option{FoldAccents("fr", "*", "xx")}, 1},
+		{"invalid limits", []Option{Limit(-1), MaxResponseSize(-1), MaxInputSize(-1), AllowedPorts([]int{0}), Diversify(1.5), Decompound(-1)}, 6},
+		{"all at once", []Option{TagWeightsString("h1"), TargetType(ContentType(9)), Language("xx"), Limit(-1)}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewWithError(tt.options...)
+			if tt.errs == 0 {
+				assert.Nil(t, err)
+				assert.NotNil(t, c)
+				return
+			}
+			assert.Nil(t, c)
+			var vErr *ValidationError
+			assert.True(t, errors.As(err, &vErr))
+			assert.Len(t, vErr.Errs, tt.errs)
+		})
+	}
+}
+
+func TestValidate_typedErrors(t *testing.T) {
+	err := New(TagWeightsString("h1:2|h2"), TargetTypeString("PDF"), Language("xx"), Limit(-1)).Validate()
+
+	var twErr *TagWeightsError
+	assert.True(t, errors.As(err, &twErr))
+	assert.Equal(t, []string{"h2"}, twErr.Entries)
+
+	var ctErr *ContentTypeError
+	assert.True(t, errors.As(err, &ctErr))
+	assert.Equal(t, "PDF", ctErr.Value)
+
+	var langErr *LanguageError
+	assert.True(t, errors.As(err, &langErr))
+	assert.Equal(t, "xx", langErr.Lang)
+
+	var limitErr *LimitError
+	assert.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, "limit", limitErr.Name)
+}
+
+func TestReadTagWeights(t *testing.T) {
+	weights, err := ReadTagWeights(strings.NewReader("h1:2|h2|p:x|a:0.5|"), String)
+	assert.Equal(t, TagWeights{"h1": 2, "a": 0.5}, weights)
+	var twErr *TagWeightsError
+	assert.True(t, errors.As(err, &twErr))
+	assert.Equal(t, []string{"h2", "p:x"}, twErr.Entries)
+
+	weights, err = ReadTagWeights(strings.NewReader("{\"h1\": 3}"), JSON)
+	assert.Nil(t, err)
+	assert.Equal(t, TagWeights{"h1": 3}, weights)
+}
+
+func TestFingerprint(t *testing.T) {
+	fp, ok := New(Limit(5), Language("en")).Fingerprint()
+	assert.True(t, ok)
+	same, _ := New(Language("en"), Limit(5)).Fingerprint()
+	assert.Equal(t, fp, same)
+	other, _ := New(Limit(6), Language("en")).Fingerprint()
+	assert.NotEqual(t, fp, other)
+
+	_, ok = New(StopWords([]string{"foo"})).Fingerprint()
+	assert.False(t, ok)
+	_, ok = New(PositionWeights(StepDecay(1, 2))).Fingerprint()
+	assert.False(t, ok)
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError aggregates all problems found in the configuration.
+type ValidationError struct {
+	Errs []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("invalid configuration: %s", strings.Join(msgs, "; "))
+}
+
+// Unwrap allows to inspect aggregated errors with errors.Is & errors.As.
+func (e *ValidationError) Unwrap() []error {
+	return e.Errs
+}
+
+// TagWeightsError is returned when tag weights can't be read or have malformed entries.
+type TagWeightsError struct {
+	Name    string   // e.g. "tag weights", "extra tag weights"
+	Source  string   // file, if weights are read from a file
+	Entries []string // malformed entries
+	Err     error
+}
+
+func (e *TagWeightsError) Error() string {
+	name := e.Name
+	if e.Source != "" {
+		name = fmt.Sprintf("%s %q", name, e.Source)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("can't read %s: %v", name, e.Err)
+	}
+	return fmt.Sprintf("malformed %s: %s", name, strings.Join(e.Entries, ", "))
+}
+
+func (e *TagWeightsError) Unwrap() error {
+	return e.Err
+}
+
+// ContentTypeError is returned for unknown content types.
+type ContentTypeError struct {
+	Value string
+}
+
+func (e *ContentTypeError) Error() string {
+	return fmt.Sprintf("unknown content type %q, allowed values: %s", e.Value, strings.Join(ContentTypes[:], ", "))
+}
+
+// AlgorithmError is returned for unknown keyword extraction algorithms.
+type AlgorithmError struct {
+	Value string
+}
+
+func (e *AlgorithmError) Error() string {
+	return fmt.Sprintf("unknown algorithm %q, allowed values: %s", e.Value, strings.Join(Algorithms[:], ", "))
+}
+
+// DecayError is returned for malformed positional decays (see DecayOf).
+type DecayError struct {
+	Value string
+}
+
+func (e *DecayError) Error() string {
+	return fmt.Sprintf("malformed decay %q, expected <kind>:<first>:<boost>[:<span>] with kind one of: %s", e.Value, strings.Join(Decays[:], ", "))
+}
+
+// TokenRuleError is returned for unknown rules of the token preservation.
+type TokenRuleError struct {
+	Value string
+}
+
+func (e *TokenRuleError) Error() string {
+	return fmt.Sprintf("unknown token rule %q, allowed values: all, %s", e.Value, strings.Join(TokenRules[:], ", "))
+}
+
+// TokenPatternError is returned for the patterns of the token preservation, which don't compile.
+type TokenPatternError struct {
+	Value string
+	Err   error
+}
+
+func (e *TokenPatternError) Error() string {
+	return fmt.Sprintf("invalid token pattern %q: %v", e.Value, e.Err)
+}
+
+func (e *TokenPatternError) Unwrap() error {
+	return e.Err
+}
+
+// DictError is returned when user dictionary of the segmenter can't be loaded.
+type DictError struct {
+	Path string
+	Err  error
+}
+
+func (e *DictError) Error() string {
+	return fmt.Sprintf("can't load dictionary %q: %v", e.Path, e.Err)
+}
+
+func (e *DictError) Unwrap() error {
+	return e.Err
+}
+
+// LanguageError is returned for unsupported languages.
+type LanguageError struct {
+	Lang string
+}
+
+func (e *LanguageError) Error() string {
+	return fmt.Sprintf("unsupported language %q", e.Lang)
+}
+
+// LimitError is returned for invalid limits, e.g. negative ones.
+type LimitError struct {
+	Name  string
+	Value string
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Name, e.Value)
+}
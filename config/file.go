@@ -0,0 +1,378 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	fileDir = ".tagify"
+	// EnvPrefix is the prefix of the environment variables, which override profiles,
+	// e.g. TAGIFY_LIMIT overrides "limit".
+	EnvPrefix = "TAGIFY_"
+)
+
+// names of the default configuration files in the order of lookup
+var defaultFiles = []string{"config.yaml", "config.yml", "config.toml", "config.json"}
+
+// FileFormatError is returned for configuration files of unknown format.
+type FileFormatError struct {
+	File string
+}
+
+func (e *FileFormatError) Error() string {
+	return fmt.Sprintf("unknown format of the configuration file %q, supported extensions: .yaml, .yml, .toml, .json", e.File)
+}
+
+// ProfileNotFoundError is returned when named profile is missing in the configuration file.
+type ProfileNotFoundError struct {
+	Name string
+}
+
+func (e *ProfileNotFoundError) Error() string {
+	return fmt.Sprintf("profile %q is not found", e.Name)
+}
+
+// EnvError is returned when environment variable has invalid value.
+type EnvError struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+func (e *EnvError) Error() string {
+	return fmt.Sprintf("invalid value %q of %s: %v", e.Value, e.Name, e.Err)
+}
+
+func (e *EnvError) Unwrap() error {
+	return e.Err
+}
+
+// Profile is a set of options, which is read from the configuration file.
+type Profile struct {
+	Lang            string     `json:"lang,omitempty" yaml:"lang,omitempty" toml:"lang,omitempty"`
+	ContentType     string     `json:"content_type,omitempty" yaml:"content_type,omitempty" toml:"content_type,omitempty"`
+	Limit           *int       `json:"limit,omitempty" yaml:"limit,omitempty" toml:"limit,omitempty"`
+	Timeout         string     `json:"timeout,omitempty" yaml:"timeout,omitempty" toml:"timeout,omitempty"`
+	UserAgent       string     `json:"user_agent,omitempty" yaml:"user_agent,omitempty" toml:"user_agent,omitempty"`
+	Cache           string     `json:"cache,omitempty" yaml:"cache,omitempty" toml:"cache,omitempty"`
+	TagWeights      TagWeights `json:"tag_weights,omitempty" yaml:"tag_weights,omitempty" toml:"tag_weights,omitempty"`
+	ExtraTagWeights TagWeights `json:"extra_tag_weights,omitempty" yaml:"extra_tag_weights,omitempty" toml:"extra_tag_weights,omitempty"`
+	ExcludeTags     []string   `json:"exclude_tags,omitempty" yaml:"exclude_tags,omitempty" toml:"exclude_tags,omitempty"`
+	AllTagWeights   *bool      `json:"all_tag_weights,omitempty" yaml:"all_tag_weights,omitempty" toml:"all_tag_weights,omitempty"`
+	StopWords       []string   `json:"stop_words,omitempty" yaml:"stop_words,omitempty" toml:"stop_words,omitempty"`
+	NoStopWords     *bool      `json:"no_stop_words,omitempty" yaml:"no_stop_words,omitempty" toml:"no_stop_words,omitempty"`
+	ContentOnly     *bool      `json:"content_only,omitempty" yaml:"content_only,omitempty" toml:"content_only,omitempty"`
+	AdjustScores    *bool      `json:"adjust_scores,omitempty" yaml:"adjust_scores,omitempty" toml:"adjust_scores,omitempty"`
+	Verbose         *bool      `json:"verbose,omitempty" yaml:"verbose,omitempty" toml:"verbose,omitempty"`
+	// Extensions are names of the installed extension Apps, they are not part of the Options.
+	Extensions []string `json:"extensions,omitempty" yaml:"extensions,omitempty" toml:"extensions,omitempty"`
+}
+
+// File is the configuration file: top level options are the defaults,
+// named profiles override them.
+type File struct {
+	Profile  `yaml:",inline"`
+	Profiles map[string]*Profile `json:"profiles,omitempty" yaml:"profiles,omitempty" toml:"profiles,omitempty"`
+}
+
+// DefaultFilePath returns location of the existing default configuration file,
+// which is one of the "$HOME/.tagify/config.{yaml,yml,toml,json}", empty string is returned if there is none.
+func DefaultFilePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range defaultFiles {
+		path := filepath.Join(home, fileDir, name)
+		if _, err = os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// LoadFile reads configuration file, format is defined by the extension of the file.
+func LoadFile(path string) (*File, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file %q: %w", path, err)
+	}
+	f := &File{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(bs))
+		dec.KnownFields(true)
+		if err = dec.Decode(f); errors.Is(err, io.EOF) {
+			err = nil // empty file
+		}
+	case ".toml":
+		var md toml.MetaData
+		if md, err = toml.Decode(string(bs), f); err == nil && len(md.Undecoded()) > 0 {
+			err = fmt.Errorf("unknown keys: %v", md.Undecoded())
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(bs))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(f)
+	default:
+		return nil, &FileFormatError{File: path}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode configuration file %q: %w", path, err)
+	}
+	return f, nil
+}
+
+// Get returns defaults of the file overridden by the profile with the given name,
+// only defaults are returned if name is empty.
+func (f *File) Get(name string) (*Profile, error) {
+	p := f.Profile
+	if name == "" {
+		return &p, nil
+	}
+	named, ok := f.Profiles[name]
+	if !ok || named == nil {
+		return nil, &ProfileNotFoundError{Name: name}
+	}
+	p.merge(named)
+	return &p, nil
+}
+
+// LoadProfile reads the named profile from the configuration file and applies environment overrides,
+// if path is empty, then only the environment variables are used.
+func LoadProfile(path, name string) (*Profile, error) {
+	p := &Profile{}
+	if path != "" {
+		f, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if p, err = f.Get(name); err != nil {
+			return nil, err
+		}
+	} else if name != "" {
+		return nil, &ProfileNotFoundError{Name: name}
+	}
+	if err := p.ApplyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// merge overrides the profile with the values set in the given one.
+func (p *Profile) merge(o *Profile) {
+	if o.Lang != "" {
+		p.Lang = o.Lang
+	}
+	if o.ContentType != "" {
+		p.ContentType = o.ContentType
+	}
+	if o.Limit != nil {
+		p.Limit = o.Limit
+	}
+	if o.Timeout != "" {
+		p.Timeout = o.Timeout
+	}
+	if o.UserAgent != "" {
+		p.UserAgent = o.UserAgent
+	}
+	if o.Cache != "" {
+		p.Cache = o.Cache
+	}
+	if o.TagWeights != nil {
+		p.TagWeights = o.TagWeights
+	}
+	if o.ExtraTagWeights != nil {
+		p.ExtraTagWeights = o.ExtraTagWeights
+	}
+	if o.ExcludeTags != nil {
+		p.ExcludeTags = o.ExcludeTags
+	}
+	if o.AllTagWeights != nil {
+		p.AllTagWeights = o.AllTagWeights
+	}
+	if o.StopWords != nil {
+		p.StopWords = o.StopWords
+	}
+	if o.NoStopWords != nil {
+		p.NoStopWords = o.NoStopWords
+	}
+	if o.ContentOnly != nil {
+		p.ContentOnly = o.ContentOnly
+	}
+	if o.AdjustScores != nil {
+		p.AdjustScores = o.AdjustScores
+	}
+	if o.Verbose != nil {
+		p.Verbose = o.Verbose
+	}
+	if o.Extensions != nil {
+		p.Extensions = o.Extensions
+	}
+}
+
+// ApplyEnv overrides the profile with the environment variables (see EnvPrefix),
+// tag weights are in the form of <tag1>:<score1>|<tag2>:<score2>, lists are comma separated.
+func (p *Profile) ApplyEnv(lookup func(string) (string, bool)) error {
+	errs := []error{}
+	str := func(name string, dst *string) {
+		if v, ok := lookup(EnvPrefix + name); ok {
+			*dst = v
+		}
+	}
+	list := func(name string, dst *[]string) {
+		if v, ok := lookup(EnvPrefix + name); ok {
+			*dst = splitList(v)
+		}
+	}
+	boolean := func(name string, dst **bool) {
+		if v, ok := lookup(EnvPrefix + name); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, &EnvError{Name: EnvPrefix + name, Value: v, Err: err})
+				return
+			}
+			*dst = &b
+		}
+	}
+	weights := func(name string, dst *TagWeights) {
+		if v, ok := lookup(EnvPrefix + name); ok {
+			w, err := ReadTagWeights(strings.NewReader(v), String)
+			if err != nil {
+				errs = append(errs, &EnvError{Name: EnvPrefix + name, Value: v, Err: err})
+				return
+			}
+			*dst = w
+		}
+	}
+
+	str("LANG", &p.Lang)
+	str("CONTENT_TYPE", &p.ContentType)
+	if v, ok := lookup(EnvPrefix + "LIMIT"); ok {
+		if n, err := strconv.Atoi(v); err != nil {
+			errs = append(errs, &EnvError{Name: EnvPrefix + "LIMIT", Value: v, Err: err})
+		} else {
+			p.Limit = &n
+		}
+	}
+	str("TIMEOUT", &p.Timeout)
+	str("USER_AGENT", &p.UserAgent)
+	str("CACHE", &p.Cache)
+	weights("TAG_WEIGHTS", &p.TagWeights)
+	weights("EXTRA_TAG_WEIGHTS", &p.ExtraTagWeights)
+	list("EXCLUDE_TAGS", &p.ExcludeTags)
+	boolean("ALL_TAG_WEIGHTS", &p.AllTagWeights)
+	list("STOP_WORDS", &p.StopWords)
+	boolean("NO_STOP_WORDS", &p.NoStopWords)
+	boolean("CONTENT_ONLY", &p.ContentOnly)
+	boolean("ADJUST_SCORES", &p.AdjustScores)
+	boolean("VERBOSE", &p.Verbose)
+	list("EXTENSIONS", &p.Extensions)
+
+	return errors.Join(errs...)
+}
+
+// Options converts the profile into the options, invalid values are reported by Config.Validate.
+func (p *Profile) Options() []Option {
+	options := []Option{}
+	if p.Lang != "" {
+		options = append(options, Language(p.Lang))
+	}
+	if p.ContentType != "" {
+		options = append(options, TargetTypeString(p.ContentType))
+	}
+	if p.Limit != nil {
+		options = append(options, Limit(*p.Limit))
+	}
+	if p.Timeout != "" {
+		options = append(options, timeoutString(p.Timeout))
+	}
+	if p.UserAgent != "" {
+		options = append(options, UserAgent(p.UserAgent))
+	}
+	if p.Cache != "" {
+		options = append(options, Cache(p.Cache))
+	}
+	if p.TagWeights != nil {
+		options = append(options, tagWeights(p.TagWeights))
+	}
+	if p.ExtraTagWeights != nil {
+		options = append(options, extraTagWeights(p.ExtraTagWeights))
+	}
+	if p.ExcludeTags != nil {
+		options = append(options, excludeTags(p.ExcludeTags))
+	}
+	if p.AllTagWeights != nil {
+		options = append(options, AllTagWeights(*p.AllTagWeights))
+	}
+	if p.StopWords != nil {
+		options = append(options, StopWords(p.StopWords))
+	}
+	if p.NoStopWords != nil {
+		options = append(options, NoStopWords(*p.NoStopWords))
+	}
+	if p.ContentOnly != nil {
+		options = append(options, ContentOnly(*p.ContentOnly))
+	}
+	if p.AdjustScores != nil {
+		options = append(options, AdjustScores(*p.AdjustScores))
+	}
+	if p.Verbose != nil {
+		options = append(options, Verbose(*p.Verbose))
+	}
+	return options
+}
+
+func timeoutString(v string) Option {
+	return func(c *Config) {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			c.errs = append(c.errs, &LimitError{Name: "timeout", Value: v})
+			return
+		}
+		c.Timeout = d
+	}
+}
+
+func tagWeights(v TagWeights) Option {
+	return func(c *Config) {
+		c.TagWeights = MergeTagWeights(v, nil)
+	}
+}
+
+func extraTagWeights(v TagWeights) Option {
+	return func(c *Config) {
+		c.ExtraTagWeights = MergeTagWeights(v, nil)
+	}
+}
+
+func excludeTags(v []string) Option {
+	return func(c *Config) {
+		c.ExcludeTags = make(TagWeights, len(v))
+		for _, t := range v {
+			c.ExcludeTags[t] = 0
+		}
+	}
+}
+
+func splitList(v string) []string {
+	res := []string{}
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			res = append(res, s)
+		}
+	}
+	return res
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fileTests = []struct {
+	name     string
+	contents string
+}{
+	{
+		"config.yaml",
+		`
+lang: en
+limit: 5
+tag_weights:
+  h1: 2
+exclude_tags: [nav]
+profiles:
+  news:
+    limit: 10
+    stop_words: [breaking]
+    extensions: [my-ext]
+`,
+	},
+	{
+		"config.toml",
+		`
+lang = "en"
+limit = 5
+exclude_tags = ["nav"]
+
+[tag_weights]
+h1 = 2
+
+[profiles.news]
+limit = 10
+stop_words = ["breaking"]
+extensions = ["my-ext"]
+`,
+	},
+	{
+		"config.json",
+		`{
+  "lang": "en",
+  "limit": 5,
+  "tag_weights": {"h1": 2},
+  "exclude_tags": ["nav"],
+  "profiles": {
+    "news": {"limit": 10, "stop_words": ["breaking"], "extensions": ["my-ext"]}
+  }
+}`,
+	},
+}
+
+func TestLoadFile(t *testing.T) {
+	for _, tt := range fileTests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.name)
+			assert.Nil(t, os.WriteFile(path, []byte(tt.contents), 0644))
+
+			f, err := LoadFile(path)
+			assert.Nil(t, err)
+
+			p, err := f.Get("")
+			assert.Nil(t, err)
+			c := New(p.Options()...)
+			assert.Nil(t, c.Validate())
+			assert.Equal(t, "en", c.Lang)
+			assert.Equal(t, 5, c.Limit)
+			assert.Equal(t, TagWeights{"h1": 2}, c.TagWeights)
+			assert.Contains(t, c.ExcludeTags, "nav")
+			assert.Nil(t, c.StopWords)
+
+			p, err = f.Get("news")
+			assert.Nil(t, err)
+			c = New(p.Options()...)
+			assert.Equal(t, "en", c.Lang)
+			assert.Equal(t, 10, c.Limit)
+			assert.Equal(t, TagWeights{"h1": 2}, c.TagWeights)
+			assert.True(t, c.StopWords.IsStopWord("breaking"))
+			assert.Equal(t, []string{"my-ext"}, p.Extensions)
+
+			_, err = f.Get("docs")
+			var notFound *ProfileNotFoundError
+			assert.True(t, errors.As(err, &notFound))
+		})
+	}
+}
+
+func TestLoadFile_errors(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "config.ini")
+	assert.Nil(t, os.WriteFile(path, []byte("limit=5"), 0644))
+	_, err := LoadFile(path)
+	var formatErr *FileFormatError
+	assert.True(t, errors.As(err, &formatErr))
+
+	path = filepath.Join(dir, "config.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte("limmit: 5"), 0644))
+	_, err = LoadFile(path)
+	assert.ErrorContains(t, err, "limmit")
+}
+
+func TestProfile_ApplyEnv(t *testing.T) {
+	limit := 5
+	p := &Profile{Lang: "en", Limit: &limit}
+	env := map[string]string{
+		"TAGIFY_LIMIT":             "7",
+		"TAGIFY_NO_STOP_WORDS":     "true",
+		"TAGIFY_EXTRA_TAG_WEIGHTS": "h1:3|h2:2",
+		"TAGIFY_EXTENSIONS":        "a, b",
+	}
+	lookup := func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	}
+	assert.Nil(t, p.ApplyEnv(lookup))
+	c := New(p.Options()...)
+	assert.Equal(t, "en", c.Lang)
+	assert.Equal(t, 7, c.Limit)
+	assert.True(t, c.NoStopWords)
+	assert.Equal(t, TagWeights{"h1": 3, "h2": 2}, c.ExtraTagWeights)
+	assert.Equal(t, []string{"a", "b"}, p.Extensions)
+
+	env["TAGIFY_LIMIT"] = "many"
+	env["TAGIFY_VERBOSE"] = "maybe"
+	err := p.ApplyEnv(lookup)
+	var envErr *EnvError
+	assert.True(t, errors.As(err, &envErr))
+	assert.Equal(t, "TAGIFY_LIMIT", envErr.Name)
+}
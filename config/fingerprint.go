@@ -0,0 +1,33 @@
+package config
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// Fingerprint returns hash of the options, which affect results of the run,
+// i.e. results of the same contents are interchangeable between configurations with the same fingerprint.
+// Configurations with extensions, positional weighing or custom stop words have no fingerprint,
+// since these can't be compared.
+func (c *Config) Fingerprint() (string, bool) {
+	if len(c.Extensions) > 0 || c.PositionDecay != nil ||
+		(c.StopWords != nil && c.StopWords != stopWordsFor(c.Lang)) {
+		return "", false
+	}
+	patterns := make([]string, len(c.TokenPatterns))
+	for i, p := range c.TokenPatterns {
+		patterns[i] = p.String()
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "lang=%q skip_lang=%t section_langs=%t content_type=%d\n", c.Lang, c.SkipLang, c.SectionLangs, c.ContentType)
+	fmt.Fprintf(h, "limit=%d no_stop_words=%t content_only=%t stream=%t max_input=%d\n",
+		c.Limit, c.NoStopWords, c.ContentOnly, c.Stream, c.MaxInputSize)
+	fmt.Fprintf(h, "token_rules=%v token_patterns=%q signatures=%t\n", c.TokenRules, patterns, c.Signatures)
+	fmt.Fprintf(h, "algorithm=%d entities=%t diversify=%t lambda=%g\n", c.Algorithm, c.Entities, c.Diversify, c.MMRLambda)
+	fmt.Fprintf(h, "all_tag_weights=%t tag_weights=%v extra=%v exclude=%v adjust=%t\n",
+		c.AllTagWeights, c.TagWeights, c.ExtraTagWeights, c.ExcludeTags, c.AdjustScores)
+	fmt.Fprintf(h, "skip_normalize=%t fold_accents=%q\n", c.SkipNormalize, c.FoldAccents)
+	fmt.Fprintf(h, "user_dicts=%q@%q decompound=%t part_weight=%g\n",
+		c.UserDicts, dictVersion(c.UserDicts), c.Decompound, c.CompoundPartWeight)
+	return fmt.Sprintf("%x", h.Sum(nil)), true
+}
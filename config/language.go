@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"unicode"
 
 	"github.com/abadojack/whatlanggo"
 )
@@ -9,13 +10,13 @@ import (
 // DetectLang detects language and setups the stop words for it.
 func DetectLang(cfg *Config, controlStr string) {
 	if len(cfg.Lang) == 0 {
-		info := whatlanggo.Detect(controlStr)
 		if cfg.Verbose {
+			info := whatlanggo.Detect(controlStr)
 			fmt.Printf("detected language based on %q: %s [%s] [%s], confidence %2.f\n",
 				controlStr, info.Lang.String(), info.Lang.Iso6391(), info.Lang.Iso6393(), info.Confidence)
 		}
-		if info.IsReliable() {
-			SetLang(cfg, info.Lang.Iso6391())
+		if lang, ok := DetectLangOf(controlStr); ok {
+			SetLang(cfg, lang)
 		} else {
 			SetLang(cfg, "en")
 		}
@@ -24,11 +25,41 @@ func DetectLang(cfg *Config, controlStr string) {
 	}
 }
 
+// DetectLangOf detects language of the given text and tells whether the detection is reliable.
+func DetectLangOf(text string) (string, bool) {
+	info := whatlanggo.Detect(text)
+	if info.IsReliable() {
+		return info.Lang.Iso6391(), true
+	}
+	if isLao(text) {
+		return "lo", true
+	}
+	return "", false
+}
+
 // SetLang - updates language in configuration & sets corresponding stop-words.
 func SetLang(cfg *Config, lang string) {
+	// segmenter depends on the language
+	if cfg.Lang != lang {
+		cfg.seg = nil
+	}
 	cfg.Lang = lang
 	cfg.SetStopWords(lang)
 	if cfg.Verbose {
 		fmt.Printf("language to use: %s\n", lang)
 	}
 }
+
+// isLao tells whether the most of letters are of the Lao script, which isn't detected by whatlanggo.
+func isLao(s string) bool {
+	var letters, lao int
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.Is(unicode.Lao, r) {
+				lao++
+			}
+		}
+	}
+	return lao > 0 && lao*2 > letters
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	//go:embed dict/*.txt
+	embeddedDicts embed.FS
+
+	// scripts of the languages segmented by the maximal matching
+	maximalScripts = map[string]*unicode.RangeTable{
+		"th": unicode.Thai,
+		"lo": unicode.Lao,
+		"km": unicode.Khmer,
+		"my": unicode.Myanmar,
+	}
+
+	// matchers are loaded once per language & set of the user dictionaries,
+	// hence are shared between configurations
+	maximalMatchers = newDictCache[*MaximalMatching]()
+)
+
+// NeedsMaximalMatching tells whether the given language is segmented by the maximal matching
+// of the dictionary words, i.e. Thai, Lao, Khmer or Burmese.
+func NeedsMaximalMatching(lang string) bool {
+	_, ok := maximalScripts[lang]
+	return ok
+}
+
+// LoadMaximalMatching loads matcher for the given language with its embedded dictionary & stop words
+// along with the given user dictionaries (see UserDict), frequencies & parts of speech of the user words are ignored.
+// Matcher is loaded once per language & set of dictionaries and is shared by all configurations,
+// it is loaded again once any of the dictionaries is modified.
+func LoadMaximalMatching(lang string, paths ...string) (*MaximalMatching, error) {
+	return maximalMatchers.load(lang, paths, func() (*MaximalMatching, error) {
+		m := NewMaximalMatching(maximalScripts[lang])
+		for _, w := range embeddedWords(lang) {
+			m.add(w)
+		}
+		for _, w := range extraStopWords[lang] {
+			m.add(w)
+		}
+		for _, p := range paths {
+			err := readDict(p, func(word string, _ float64, _ ...string) error {
+				m.add(word)
+				return nil
+			})
+			if err != nil {
+				return nil, &DictError{Path: p, Err: err}
+			}
+		}
+		return m, nil
+	})
+}
+
+// embeddedWords returns words of the embedded dictionary of the given language, if any.
+func embeddedWords(lang string) []string {
+	bs, err := embeddedDicts.ReadFile("dict/" + lang + ".txt")
+	if err != nil {
+		return nil
+	}
+	var words []string
+	scanner := bufio.NewScanner(bytes.NewReader(bs))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	return words
+}
+
+type trieNode struct {
+	next map[rune]*trieNode
+	word bool
+}
+
+// MaximalMatching splits text of the given script into the fewest dictionary words,
+// while leaving as few unknown characters as possible, runs of the unknown characters
+// become single words & text of the other scripts is split by spaces.
+// It is safe for concurrent use once created.
+type MaximalMatching struct {
+	script *unicode.RangeTable
+	root   *trieNode
+}
+
+// NewMaximalMatching creates matcher for the given script & dictionary words.
+func NewMaximalMatching(script *unicode.RangeTable, words ...string) *MaximalMatching {
+	m := &MaximalMatching{script: script, root: &trieNode{}}
+	for _, w := range words {
+		m.add(w)
+	}
+	return m
+}
+
+func (m *MaximalMatching) add(word string) {
+	n := m.root
+	for _, r := range word {
+		if n.next == nil {
+			n.next = map[rune]*trieNode{}
+		}
+		child, ok := n.next[r]
+		if !ok {
+			child = &trieNode{}
+			n.next[r] = child
+		}
+		n = child
+	}
+	n.word = n != m.root
+}
+
+// Segment ...
+func (m *MaximalMatching) Segment(text []byte) [][]byte {
+	var bs [][]byte
+	for _, field := range bytes.Fields(text) {
+		// runs of the script are matched, the rest is kept as is
+		for len(field) > 0 {
+			r, _ := utf8.DecodeRune(field)
+			inScript := unicode.Is(m.script, r)
+			end := bytes.IndexFunc(field, func(r rune) bool {
+				return unicode.Is(m.script, r) != inScript && !unicode.IsMark(r)
+			})
+			if end < 0 {
+				end = len(field)
+			}
+			if inScript {
+				bs = append(bs, m.match(field[:end])...)
+			} else {
+				bs = append(bs, field[:end])
+			}
+			field = field[end:]
+		}
+	}
+	return bs
+}
+
+type matchCost struct {
+	unknown, words int
+	prev           int
+	known          bool
+}
+
+func (c matchCost) less(o matchCost) bool {
+	if c.unknown != o.unknown {
+		return c.unknown < o.unknown
+	}
+	return c.words < o.words
+}
+
+// match splits run of the script into the dictionary words,
+// words start & end only at the boundaries of the character clusters.
+func (m *MaximalMatching) match(text []byte) [][]byte {
+	bounds := clusterBounds(text)
+	n := len(bounds) - 1
+	best := make([]*matchCost, n+1)
+	best[0] = &matchCost{}
+	relax := func(from, to int, known bool) {
+		c := matchCost{unknown: best[from].unknown, words: best[from].words + 1, prev: from, known: known}
+		if !known {
+			c.unknown++
+		}
+		if best[to] == nil || c.less(*best[to]) {
+			best[to] = &c
+		}
+	}
+	for i := 0; i < n; i++ {
+		if best[i] == nil {
+			continue
+		}
+		relax(i, i+1, false)
+		node := m.root
+		for j := i; j < n && node != nil; j++ {
+			for _, r := range string(text[bounds[j]:bounds[j+1]]) {
+				if node = node.next[r]; node == nil {
+					break
+				}
+			}
+			if node != nil && node.word {
+				relax(i, j+1, true)
+			}
+		}
+	}
+
+	// words are collected backwards, adjacent unknown clusters are joined
+	var words [][]byte
+	var unknownEnd int
+	for i := n; i > 0; i = best[i].prev {
+		c := best[i]
+		if !c.known {
+			if unknownEnd == 0 {
+				unknownEnd = bounds[i]
+			}
+			if p := c.prev; p > 0 && !best[p].known {
+				continue
+			}
+			words = append(words, text[bounds[c.prev]:unknownEnd])
+			unknownEnd = 0
+			continue
+		}
+		words = append(words, text[bounds[c.prev]:bounds[i]])
+	}
+	for i, j := 0, len(words)-1; i < j; i, j = i+1, j-1 {
+		words[i], words[j] = words[j], words[i]
+	}
+	return words
+}
+
+// clusterBounds returns byte offsets of the character clusters, i.e. base characters with their marks,
+// stacked consonants of Khmer (after coeng) & Burmese (after virama) belong to the preceding cluster.
+func clusterBounds(text []byte) []int {
+	bounds := []int{0}
+	var prev rune
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRune(text[i:])
+		if i > 0 && !unicode.IsMark(r) && prev != '្' && prev != '္' {
+			bounds = append(bounds, i)
+		}
+		prev = r
+		i += size
+	}
+	return append(bounds, len(text))
+}
@@ -1,10 +1,6 @@
 package config
 
 import (
-	"bufio"
-	"fmt"
-	"os"
-	"strings"
 	"time"
 
 	"github.com/zoomio/stopwords"
@@ -71,6 +67,52 @@ var (
 		}
 	}
 
+	// Cache enables on-disk HTTP cache in the given directory,
+	// unchanged web pages are not re-fetched nor re-processed.
+	Cache = func(dir string) Option {
+		return func(c *Config) {
+			c.CacheDir = dir
+		}
+	}
+
+	// BlockPrivateNetworks prohibits calls to private, loopback & link-local addresses,
+	// addresses are checked after resolving the host.
+	BlockPrivateNetworks = func(v bool) Option {
+		return func(c *Config) {
+			c.BlockPrivateNetworks = v
+		}
+	}
+
+	// AllowedSchemes restricts URL schemes of the sources, e.g. "https".
+	AllowedSchemes = func(v []string) Option {
+		return func(c *Config) {
+			c.AllowedSchemes = make([]string, len(v))
+			copy(c.AllowedSchemes, v)
+		}
+	}
+
+	// AllowedPorts restricts ports of the sources, e.g. 80 & 443.
+	AllowedPorts = func(v []int) Option {
+		return func(c *Config) {
+			c.AllowedPorts = make([]int, len(v))
+			copy(c.AllowedPorts, v)
+		}
+	}
+
+	// MaxResponseSize caps size (in bytes) of the HTTP responses.
+	MaxResponseSize = func(v int64) Option {
+		return func(c *Config) {
+			c.MaxResponseSize = v
+		}
+	}
+
+	// MaxRedirects caps amount of HTTP redirects to follow, 0 disables redirects.
+	MaxRedirects = func(v int) Option {
+		return func(c *Config) {
+			c.MaxRedirects = &v
+		}
+	}
+
 	// Content sets content of the target.
 	Content = func(v string) Option {
 		return func(c *Config) {
@@ -85,6 +127,17 @@ var (
 		}
 	}
 
+	// TargetTypeString sets content type of the target by its name (see ContentTypes),
+	// unknown names are reported by Config.Validate.
+	TargetTypeString = func(v string) Option {
+		return func(c *Config) {
+			c.ContentType = ContentTypeOf(v)
+			if c.ContentType == Unknown && v != Unknown.String() {
+				c.errs = append(c.errs, &ContentTypeError{Value: v})
+			}
+		}
+	}
+
 	// Limit sets the limit of tags for the target.
 	Limit = func(v int) Option {
 		return func(c *Config) {
@@ -106,6 +159,14 @@ var (
 		}
 	}
 
+	// SectionLangs enables detection of the languages per section (e.g. paragraph) of the document,
+	// each section uses stop words & segmenter of its language, shares of the languages are reported by model.Meta.Langs.
+	SectionLangs = func(v bool) Option {
+		return func(c *Config) {
+			c.SectionLangs = v
+		}
+	}
+
 	// StopWords allows to provide a custom set of stop-words.
 	StopWords = func(v []string) Option {
 		return func(c *Config) {
@@ -127,52 +188,188 @@ var (
 		}
 	}
 
-	// TagWeightsString ...
+	// Stream tells text processor to read input line by line with bounded memory,
+	// suitable for very large inputs e.g. logs & transcripts (Text only).
+	// Memory is bounded by the distinct tokens, hence keywords algorithms (see KeywordAlgorithm) aren't applied
+	// and diversification (see Diversify) relies only on the similarity of the tags, not on their co-occurrence.
+	Stream = func(v bool) Option {
+		return func(c *Config) {
+			c.Stream = v
+		}
+	}
+
+	// MaxInputSize caps size (in bytes) of the input to process.
+	MaxInputSize = func(v int64) Option {
+		return func(c *Config) {
+			c.MaxInputSize = v
+		}
+	}
+
+	// PreserveTokens keeps tokens of the given kinds as is (lowercased),
+	// instead of splitting them into words, e.g. "C++", "#golang" or "Go1.22".
+	PreserveTokens = func(v ...TokenRule) Option {
+		return func(c *Config) {
+			c.TokenRules = append(c.TokenRules, v...)
+		}
+	}
+
+	// PreserveString sets kinds of the tokens to keep as is by their comma separated names (see TokenRules),
+	// "all" enables all of them, unknown names are reported by Config.Validate.
+	PreserveString = func(v string) Option {
+		return func(c *Config) {
+			c.addTokenRules(v)
+		}
+	}
+
+	// PreservePatterns keeps tokens matching the given regular expressions as is (lowercased),
+	// patterns, which don't compile, are reported by Config.Validate.
+	PreservePatterns = func(v ...string) Option {
+		return func(c *Config) {
+			c.addTokenPatterns(v)
+		}
+	}
+
+	// Signatures tells processors to compute SimHash & MinHash signatures
+	// of the document (see model.Meta), which are used to find near-duplicates.
+	Signatures = func(v bool) Option {
+		return func(c *Config) {
+			c.Signatures = v
+		}
+	}
+
+	// KeywordAlgorithm sets algorithm of the keyword extraction,
+	// RAKE & YAKE give multi-word tags with their native scores.
+	KeywordAlgorithm = func(v Algorithm) Option {
+		return func(c *Config) {
+			c.Algorithm = v
+		}
+	}
+
+	// AlgorithmString sets algorithm of the keyword extraction by its name (see Algorithms),
+	// unknown names are reported by Config.Validate.
+	AlgorithmString = func(v string) Option {
+		return func(c *Config) {
+			var ok bool
+			if c.Algorithm, ok = AlgorithmOf(v); !ok {
+				c.errs = append(c.errs, &AlgorithmError{Value: v})
+			}
+		}
+	}
+
+	// Entities tells processors to detect named entities before lowercasing:
+	// capitalized sequences of words (except single words starting the sentences), acronyms & quoted titles,
+	// which are kept as tags with the original casing (see model.Tag.Entity).
+	Entities = func(v bool) Option {
+		return func(c *Config) {
+			c.Entities = v
+		}
+	}
+
+	// Diversify re-ranks tags with Maximal Marginal Relevance, so that the tags
+	// which co-occur with or look like the better ranked ones are penalized,
+	// lambda is within the interval of 0.0 (only diversity) to 1.0 (only relevance).
+	Diversify = func(lambda float64) Option {
+		return func(c *Config) {
+			c.Diversify = true
+			c.MMRLambda = lambda
+		}
+	}
+
+	// UserDict adds user dictionaries (e.g. brand names & product terms) to the segmenter of the languages
+	// without spaces between words (see NeedsDict, NeedsMaximalMatching & LoadUserDict) and of the compounds (see Decompound),
+	// dictionaries are loaded once text of such language is segmented, errors are reported by Config.Validate.
+	UserDict = func(paths ...string) Option {
+		return func(c *Config) {
+			for _, p := range paths {
+				if err := checkDict(p); err != nil {
+					c.errs = append(c.errs, err)
+				}
+			}
+			c.UserDicts = append(c.UserDicts, paths...)
+		}
+	}
+
+	// SkipNormalize disables the Unicode normalization & script-specific cleanup of the tokens (see util.NormalizeText).
+	SkipNormalize = func(v bool) Option {
+		return func(c *Config) {
+			c.SkipNormalize = v
+		}
+	}
+
+	// FoldAccents folds tokens of the given languages into the letters without accents, e.g. "café" into "cafe",
+	// "*" folds tokens of any language, unsupported languages are reported by Config.Validate.
+	FoldAccents = func(langs ...string) Option {
+		return func(c *Config) {
+			c.FoldAccents = append(c.FoldAccents, langs...)
+		}
+	}
+
+	// Decompound splits German & Dutch compounds into the known words (see LoadDecompounder),
+	// parts follow their compounds & are weighted with the given multiplier of the weights of the compounds.
+	Decompound = func(weight float64) Option {
+		return func(c *Config) {
+			c.Decompound = true
+			c.CompoundPartWeight = weight
+		}
+	}
+
+	// TagWeightsString sets tag weights in the form of <tag1>:<score1>|<tag2>:<score2>,
+	// malformed entries are reported by Config.Validate.
 	TagWeightsString = func(v string) Option {
 		return func(c *Config) {
-			c.TagWeights = ParseTagWeights(strings.NewReader(v), String)
+			c.TagWeights = c.readTagWeights("tag weights", v, String)
 		}
 	}
 
-	// TagWeightsJSON ...
+	// TagWeightsJSON reads tag weights from the JSON file,
+	// errors are reported by Config.Validate.
 	TagWeightsJSON = func(v string) Option {
 		return func(c *Config) {
-			f, err := os.Open(v)
-			if err != nil {
-				println(fmt.Errorf("error: can't open JSON file [%s]: %w", v, err))
-				return
-			}
-			r := bufio.NewReader(f)
-			c.TagWeights = ParseTagWeights(r, JSON)
-			f.Close()
+			c.TagWeights = c.readTagWeights("tag weights", v, JSON)
 		}
 	}
 
-	// ExtraTagWeightsString ...
+	// ExtraTagWeightsString sets extra tag weights in the form of <tag1>:<score1>|<tag2>:<score2>,
+	// malformed entries are reported by Config.Validate.
 	ExtraTagWeightsString = func(v string) Option {
 		return func(c *Config) {
-			c.ExtraTagWeights = ParseTagWeights(strings.NewReader(v), String)
+			c.ExtraTagWeights = c.readTagWeights("extra tag weights", v, String)
 		}
 	}
 
-	// TagWeightsJSON ...
+	// ExtraTagWeightsJSON reads extra tag weights from the JSON file,
+	// errors are reported by Config.Validate.
 	ExtraTagWeightsJSON = func(v string) Option {
 		return func(c *Config) {
-			f, err := os.Open(v)
-			if err != nil {
-				println(fmt.Errorf("error: can't open JSON file [%s]: %w", v, err))
-				return
+			c.ExtraTagWeights = c.readTagWeights("extra tag weights", v, JSON)
+		}
+	}
+
+	// PositionWeights weighs tokens by position of their sentences in the document,
+	// the first paragraph after the h1 heading (HTML & Markdown) is weighted as the first sentence,
+	// see StepDecay, LinearDecay & ExpDecay.
+	PositionWeights = func(v Decay) Option {
+		return func(c *Config) {
+			c.PositionDecay = v
+		}
+	}
+
+	// PositionString sets positional weighing in the form of <kind>:<first>:<boost>[:<span>] (see DecayOf),
+	// malformed values are reported by Config.Validate.
+	PositionString = func(v string) Option {
+		return func(c *Config) {
+			var ok bool
+			if c.PositionDecay, ok = DecayOf(v); !ok {
+				c.errs = append(c.errs, &DecayError{Value: v})
 			}
-			r := bufio.NewReader(f)
-			c.ExtraTagWeights = ParseTagWeights(r, JSON)
-			f.Close()
 		}
 	}
 
-	// ExcludeTagsString ...
+	// ExcludeTagsString sets tags to exclude in the form of <tag1>:<score1>|<tag2>:<score2>,
+	// malformed entries are reported by Config.Validate.
 	ExcludeTagsString = func(v string) Option {
 		return func(c *Config) {
-			c.ExcludeTags = ParseTagWeights(strings.NewReader(v), String)
+			c.ExcludeTags = c.readTagWeights("exclude tags", v, String)
 		}
 	}
 
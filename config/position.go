@@ -0,0 +1,91 @@
+package config
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	Decays = [...]string{
+		"step",
+		"linear",
+		"exp",
+	}
+)
+
+// Decay gives the multiplier of the weights of the tokens
+// in the sentence at the given position (starting from 0).
+type Decay func(pos int) float64
+
+// StepDecay boosts the first n sentences, the rest are weighted as usual.
+func StepDecay(n int, boost float64) Decay {
+	return func(pos int) float64 {
+		if pos < n {
+			return boost
+		}
+		return 1
+	}
+}
+
+// LinearDecay boosts the first n sentences,
+// the boost of the following ones decreases linearly and is gone after span sentences.
+func LinearDecay(n int, boost float64, span int) Decay {
+	return func(pos int) float64 {
+		if pos < n {
+			return boost
+		}
+		if span <= 0 {
+			return 1
+		}
+		return 1 + (boost-1)*math.Max(0, 1-float64(pos-n+1)/float64(span))
+	}
+}
+
+// ExpDecay boosts the first n sentences,
+// the boost of the following ones halves every halfLife sentences.
+func ExpDecay(n int, boost float64, halfLife int) Decay {
+	return func(pos int) float64 {
+		if pos < n {
+			return boost
+		}
+		if halfLife <= 0 {
+			return 1
+		}
+		return 1 + (boost-1)*math.Pow(0.5, float64(pos-n+1)/float64(halfLife))
+	}
+}
+
+// DecayOf returns Decay based on the string value in the form of <kind>:<first>:<boost>[:<span>],
+// where kind is one of Decays and span (half-life for the "exp") defaults to first,
+// e.g. "exp:3:2" boosts first 3 sentences twice, the boost of the following ones halves every 3 sentences.
+// Tells whether the value is valid.
+func DecayOf(v string) (Decay, bool) {
+	parts := strings.Split(v, ":")
+	if len(parts) < 3 || len(parts) > 4 {
+		return nil, false
+	}
+	n, err := strconv.Atoi(parts[1])
+	if err != nil || n < 0 {
+		return nil, false
+	}
+	boost, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil || boost < 0 {
+		return nil, false
+	}
+	span := n
+	if len(parts) == 4 {
+		if span, err = strconv.Atoi(parts[3]); err != nil || span < 0 {
+			return nil, false
+		}
+	}
+	switch parts[0] {
+	case "step":
+		return StepDecay(n, boost), len(parts) == 3
+	case "linear":
+		return LinearDecay(n, boost, span), true
+	case "exp":
+		return ExpDecay(n, boost, span), true
+	}
+	return nil, false
+}
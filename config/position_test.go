@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecays(t *testing.T) {
+	step := StepDecay(2, 3)
+	assert.Equal(t, []float64{3, 3, 1}, []float64{step(0), step(1), step(2)})
+
+	linear := LinearDecay(1, 3, 4)
+	assert.Equal(t, []float64{3, 2.5, 2, 1.5, 1, 1}, []float64{linear(0), linear(1), linear(2), linear(3), linear(4), linear(10)})
+
+	exp := ExpDecay(1, 3, 1)
+	assert.Equal(t, []float64{3, 2, 1.5, 1.25}, []float64{exp(0), exp(1), exp(2), exp(3)})
+}
+
+func TestDecayOf(t *testing.T) {
+	tests := []struct {
+		value string
+		ok    bool
+		want  []float64 // multipliers of the first positions
+	}{
+		{"step:1:2", true, []float64{2, 1}},
+		{"linear:1:3:2", true, []float64{3, 2, 1}},
+		{"exp:2:3", true, []float64{3, 3, 1 + 2*0.7071067811865476}},
+		{"step:1:2:3", false, nil},
+		{"exp:1", false, nil},
+		{"cubic:1:2", false, nil},
+		{"exp:x:2", false, nil},
+		{"exp:1:-2", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			decay, ok := DecayOf(tt.value)
+			assert.Equal(t, tt.ok, ok)
+			for i, w := range tt.want {
+				assert.InDelta(t, w, decay(i), 1e-9)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Rules of the token preservation
+const (
+	// Hashtags, e.g. "#golang".
+	Hashtags TokenRule = iota
+	// Mentions, e.g. "@user".
+	Mentions
+	// Versions, e.g. "1.22", "v2.0.1" or "Go1.22".
+	Versions
+	// Identifiers mixing letters & digits, e.g. "k8s", "3D" or "GPT-4".
+	Identifiers
+	// ProgLangs are names of the programming languages & platforms with symbols, e.g. "C++", "C#" or ".NET".
+	ProgLangs
+)
+
+var (
+	TokenRules = [...]string{
+		"hashtags",
+		"mentions",
+		"versions",
+		"ids",
+		"langs",
+	}
+)
+
+// TokenRule tells which kind of tokens to keep as is, instead of splitting them into words.
+type TokenRule byte
+
+// TokenRuleOf returns TokenRule based on string value and tells whether it is known.
+func TokenRuleOf(rule string) (TokenRule, bool) {
+	for i, key := range TokenRules {
+		if key == rule {
+			return TokenRule(i), true
+		}
+	}
+	return Hashtags, false
+}
+
+// String ...
+func (r TokenRule) String() string {
+	if r > ProgLangs {
+		return "unknown"
+	}
+	return TokenRules[r]
+}
+
+// PreservesTokens tells whether any tokens are preserved.
+func (c *Config) PreservesTokens() bool {
+	return len(c.TokenRules) > 0 || len(c.TokenPatterns) > 0
+}
+
+func (c *Config) addTokenRules(v string) {
+	for _, name := range strings.Split(v, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if name == "all" {
+			for i := range TokenRules {
+				c.TokenRules = append(c.TokenRules, TokenRule(i))
+			}
+			continue
+		}
+		rule, ok := TokenRuleOf(name)
+		if !ok {
+			c.errs = append(c.errs, &TokenRuleError{Value: name})
+			continue
+		}
+		c.TokenRules = append(c.TokenRules, rule)
+	}
+}
+
+func (c *Config) addTokenPatterns(v []string) {
+	for _, p := range v {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			c.errs = append(c.errs, &TokenPatternError{Value: p, Err: err})
+			continue
+		}
+		c.TokenPatterns = append(c.TokenPatterns, re)
+	}
+}
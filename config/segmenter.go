@@ -1,40 +1,189 @@
 package config
 
 import (
+	"bufio"
 	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/go-ego/gse"
 )
 
+// DefaultDictFreq is the frequency of the words of the user dictionaries, which don't specify it.
+const DefaultDictFreq = 1000
+
+var (
+	dictOnce sync.Once
+	dictSeg  *gse.Segmenter
+
+	// segmenters with the user dictionaries are loaded once per set of dictionaries,
+	// hence are shared between configurations
+	userDictSegs = newDictCache[*gse.Segmenter]()
+)
+
+// LoadDict loads dictionaries used for segmenting languages without spaces between words,
+// dictionaries are loaded only once and shared by all segmenters.
+func LoadDict() {
+	dictOnce.Do(func() {
+		seg := &gse.Segmenter{SkipLog: true}
+		seg.LoadDictEmbed()
+		dictSeg = seg
+	})
+}
+
+// LoadUserDict loads segmenter with the embedded & the given user dictionaries,
+// which have a word per line optionally followed by its frequency and part of speech, e.g. "小米手环 1000 n".
+// Segmenter is loaded once per set of dictionaries and is shared by all configurations,
+// it is loaded again once any of the dictionaries is modified.
+func LoadUserDict(paths ...string) (*gse.Segmenter, error) {
+	return userDictSegs.load("", paths, func() (*gse.Segmenter, error) {
+		seg := &gse.Segmenter{SkipLog: true}
+		if err := seg.LoadDictEmbed(); err != nil {
+			return nil, err
+		}
+		for _, p := range paths {
+			if err := readDict(p, seg.AddToken); err != nil {
+				return nil, &DictError{Path: p, Err: err}
+			}
+		}
+		seg.CalcToken()
+		return seg, nil
+	})
+}
+
+// checkDict tells whether user dictionary could be read, without loading it.
+func checkDict(path string) error {
+	err := readDict(path, func(string, float64, ...string) error { return nil })
+	if err != nil {
+		return &DictError{Path: path, Err: err}
+	}
+	return nil
+}
+
+// dictCache keeps values loaded with the user dictionaries (or their errors), one per language & set of dictionaries,
+// value is replaced once any of its dictionaries is modified.
+type dictCache[T any] struct {
+	mu      sync.Mutex
+	entries map[string]*dictEntry[T]
+}
+
+type dictEntry[T any] struct {
+	version string
+	once    sync.Once
+	value   T
+	err     error
+}
+
+func newDictCache[T any]() *dictCache[T] {
+	return &dictCache[T]{entries: map[string]*dictEntry[T]{}}
+}
+
+// load returns cached value for the given language & dictionaries or loads it,
+// values of the different keys are loaded concurrently.
+func (c *dictCache[T]) load(lang string, paths []string, load func() (T, error)) (T, error) {
+	key := lang + "\n" + strings.Join(paths, "\n")
+	version := dictVersion(paths)
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if !ok || e.version != version {
+		e = &dictEntry[T]{version: version}
+		c.entries[key] = e
+	}
+	c.mu.Unlock()
+
+	e.once.Do(func() {
+		e.value, e.err = load()
+	})
+	return e.value, e.err
+}
+
+// dictVersion identifies contents of the given dictionaries by their modification times & sizes.
+func dictVersion(paths []string) string {
+	var sb strings.Builder
+	for _, p := range paths {
+		if fi, err := os.Stat(p); err == nil {
+			fmt.Fprintf(&sb, "%d:%d\n", fi.ModTime().UnixNano(), fi.Size())
+		} else {
+			sb.WriteString("-\n")
+		}
+	}
+	return sb.String()
+}
+
+// readDict reads user dictionary, which has a word per line optionally followed by its frequency and part of speech.
+func readDict(path string, add func(word string, freq float64, pos ...string) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		freq := float64(DefaultDictFreq)
+		var pos []string
+		if len(fields) > 1 {
+			if freq, err = strconv.ParseFloat(fields[1], 64); err != nil {
+				return err
+			}
+			pos = fields[2:]
+		}
+		if err = add(fields[0], freq, pos...); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// NeedsDict tells whether the given language is segmented using dictionaries.
+func NeedsDict(lang string) bool {
+	return lang == "zh" || lang == "ja" || lang == "ko"
+}
+
 type Segmenter interface {
 	Segment(text []byte) [][]byte
 }
 
+// DefaultSegmenter splits text by spaces or by dictionaries (see NeedsDict & NeedsMaximalMatching),
+// dictionaries are resolved once the first text is segmented.
+// It is safe for concurrent use.
 type DefaultSegmenter struct {
-	lang string
-	seg  gse.Segmenter
+	lang      string
+	userDicts []string
+
+	once    sync.Once
+	maximal *MaximalMatching
+	dict    *gse.Segmenter
 }
 
 func NewDefaultSegmenter(c *Config) *DefaultSegmenter {
-	seg := gse.Segmenter{SkipLog: true}
-	if c != nil {
-		seg.NotStop = c.NoStopWords
-		seg.SkipLog = !c.Verbose
-		if c.StopWords != nil {
-			seg.StopWordMap = c.StopWords.Index()
-		}
-	}
-	seg.LoadDictEmbed()
-	s := &DefaultSegmenter{seg: seg}
+	s := &DefaultSegmenter{}
 	if c != nil {
 		s.lang = c.Lang
+		s.userDicts = c.UserDicts
 	}
 	return s
 }
 
 func (s *DefaultSegmenter) Segment(text []byte) [][]byte {
-	if s.lang == "zh" || s.lang == "ja" {
-		segments := s.seg.Segment(text)
+	s.once.Do(s.resolve)
+	if s.lang == "ko" {
+		return s.segmentKo(text)
+	}
+	if s.maximal != nil {
+		return s.maximal.Segment(text)
+	}
+	if s.dict != nil {
+		segments := s.dict.Segment(text)
 		bs := make([][]byte, len(segments))
 		for k, v := range segments {
 			bs[k] = []byte(v.Token().Text())
@@ -44,6 +193,54 @@ func (s *DefaultSegmenter) Segment(text []byte) [][]byte {
 	return bytes.Fields(text)
 }
 
+// segmentKo splits Korean text by spaces, words are split further only by the known words,
+// e.g. "삼성전자가" is split into "삼성전자" & "가" once "삼성전자" is in the user dictionary.
+func (s *DefaultSegmenter) segmentKo(text []byte) [][]byte {
+	var bs [][]byte
+	for _, field := range bytes.Fields(text) {
+		// unknown syllables are joined back together
+		var unknown []byte
+		for _, v := range s.dict.Segment(field) {
+			t := v.Token()
+			if t.Pos() == "x" && utf8.RuneCountInString(t.Text()) == 1 {
+				unknown = append(unknown, t.Text()...)
+				continue
+			}
+			if len(unknown) > 0 {
+				bs = append(bs, unknown)
+				unknown = nil
+			}
+			bs = append(bs, []byte(t.Text()))
+		}
+		if len(unknown) > 0 {
+			bs = append(bs, unknown)
+		}
+	}
+	return bs
+}
+
+// resolve loads dictionaries of the language along with the user dictionaries,
+// broken user dictionaries are reported by Config.Validate, hence only the embedded dictionaries are used then.
+func (s *DefaultSegmenter) resolve() {
+	switch {
+	case NeedsMaximalMatching(s.lang):
+		m, err := LoadMaximalMatching(s.lang, s.userDicts...)
+		if err != nil {
+			m, _ = LoadMaximalMatching(s.lang)
+		}
+		s.maximal = m
+	case NeedsDict(s.lang):
+		if len(s.userDicts) > 0 {
+			if seg, err := LoadUserDict(s.userDicts...); err == nil {
+				s.dict = seg
+				return
+			}
+		}
+		LoadDict()
+		s.dict = dictSeg
+	}
+}
+
 func BytesToStrings(txts [][]byte) []string {
 	strs := make([]string, len(txts))
 	for i := 0; i < len(txts); i++ {
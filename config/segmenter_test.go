@@ -1,8 +1,12 @@
 package config
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -25,6 +29,36 @@ var splitTextToWordsTests = []struct {
 		"世界有七十亿人口",
 		"世界|有|七十|亿|人口",
 	},
+	{
+		"Thai",
+		"th",
+		"ประเทศไทยมีนักท่องเที่ยวมาก วันนี้ผมกินข้าวที่กรุงเทพ",
+		"ประเทศไทย|มี|นักท่องเที่ยว|มาก|วันนี้|ผม|กิน|ข้าว|ที่|กรุงเทพ",
+	},
+	{
+		"Thai news",
+		"th",
+		"กระทรวงสาธารณสุขแถลงสถานการณ์โรคระบาดล่าสุดเมื่อเช้านี้",
+		"กระทรวง|สาธารณสุข|แถลง|สถานการณ์|โรค|ระบาด|ล่าสุด|เมื่อ|เช้า|นี้",
+	},
+	{
+		"Lao",
+		"lo",
+		"ລັດຖະບານລາວປະກາດນະໂຍບາຍໃໝ່ກ່ຽວກັບການສຶກສາ",
+		"ລັດຖະບານ|ລາວ|ປະກາດ|ນະໂຍບາຍ|ໃໝ່|ກ່ຽວກັບ|ການສຶກສາ",
+	},
+	{
+		"Khmer",
+		"km",
+		"រដ្ឋាភិបាលកម្ពុជាបានប្រកាសគោលនយោបាយថ្មីស្តីពីការអប់រំ",
+		"រដ្ឋាភិបាល|កម្ពុជា|បាន|ប្រកាស|គោលនយោបាយ|ថ្មី|ស្តី|ពី|ការអប់រំ",
+	},
+	{
+		"Burmese",
+		"my",
+		"မြန်မာနိုင်ငံအစိုးရသည်ပညာရေးဆိုင်ရာအစီအစဉ်အသစ်ကိုကြေညာခဲ့သည်",
+		"မြန်မာနိုင်ငံ|အစိုးရ|သည်|ပညာရေး|ဆိုင်ရာ|အစီအစဉ်|အသစ်|ကို|ကြေညာ|ခဲ့|သည်",
+	},
 }
 
 func Test_SplitTextToWords(t *testing.T) {
@@ -38,3 +72,134 @@ func Test_SplitTextToWords(t *testing.T) {
 		})
 	}
 }
+
+func Test_UserDict(t *testing.T) {
+	dir := t.TempDir()
+	dict := filepath.Join(dir, "brands.txt")
+	assert.Nil(t, os.WriteFile(dict, []byte("# brands\n小米手环 1000 n\n삼성전자\n"), 0644))
+
+	tests := []struct {
+		name     string
+		lang     string
+		options  []Option
+		input    string
+		expected string
+	}{
+		{"Chinese", "zh", nil, "小米手环很好", "小米|手环|很|好"},
+		{"Chinese with user dictionary", "zh", []Option{UserDict(dict)}, "小米手环很好", "小米手环|很|好"},
+		{"Korean", "ko", nil, "삼성전자가 휴대폰을 출시했다", "삼성전자가|휴대폰을|출시했다"},
+		{"Korean with user dictionary", "ko", []Option{UserDict(dict)}, "삼성전자가 휴대폰을 출시했다", "삼성전자|가|휴대폰을|출시했다"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := NewWithError(append(tt.options, Language(tt.lang))...)
+			assert.Nil(t, err)
+			words := cfg.Segment([]byte(tt.input))
+			assert.Equal(t, tt.expected, strings.Join(BytesToStrings(words), "|"))
+		})
+	}
+
+	// segmenter is loaded once & shared between configurations
+	seg, err := LoadUserDict(dict)
+	assert.Nil(t, err)
+	same, err := LoadUserDict(dict)
+	assert.Nil(t, err)
+	assert.Same(t, seg, same)
+
+	// segmenter is loaded again once dictionary is modified
+	assert.Nil(t, os.WriteFile(dict, []byte("小米手环 1000 n\n很好 1000 a\n"), 0644))
+	modTime := time.Now().Add(time.Minute)
+	assert.Nil(t, os.Chtimes(dict, modTime, modTime))
+	cfg := New(Language("zh"), UserDict(dict))
+	assert.Equal(t, "小米手环|很好", strings.Join(BytesToStrings(cfg.Segment([]byte("小米手环很好"))), "|"))
+	reloaded, err := LoadUserDict(dict)
+	assert.Nil(t, err)
+	assert.NotSame(t, seg, reloaded)
+
+	// dictionaries aren't loaded for the languages, which don't need them
+	other := filepath.Join(dir, "other.txt")
+	assert.Nil(t, os.WriteFile(other, []byte("foo\n"), 0644))
+	New(Language("en"), UserDict(other)).Segment([]byte("foo bar"))
+	_, ok := userDictSegs.entries["\n"+other]
+	assert.False(t, ok)
+
+	_, err = NewWithError(UserDict(filepath.Join(dir, "missing.txt")))
+	var dictErr *DictError
+	assert.True(t, errors.As(err, &dictErr))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func Test_MaximalMatching(t *testing.T) {
+	dict := filepath.Join(t.TempDir(), "th.txt")
+	assert.Nil(t, os.WriteFile(dict, []byte("# brands\nซัมซุง 1000 n\n"), 0644))
+
+	tests := []struct {
+		name     string
+		options  []Option
+		input    string
+		expected string
+	}{
+		{"unknown words are joined", nil, "ผมชอบกึ๋ยกึ๋ย", "ผม|ชอบ|กึ๋ยกึ๋ย"},
+		{"without user dictionary", nil, "ผมชอบซัมซุงมาก", "ผม|ชอบ|ซัม|ซุง|มาก"},
+		{"other scripts are kept", nil, "ผมชอบGo1.22มาก", "ผม|ชอบ|Go1.22|มาก"},
+		{"user dictionary", []Option{UserDict(dict)}, "ผมชอบซัมซุงมาก", "ผม|ชอบ|ซัมซุง|มาก"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := NewWithError(append(tt.options, Language("th"))...)
+			assert.Nil(t, err)
+			words := cfg.Segment([]byte(tt.input))
+			assert.Equal(t, tt.expected, strings.Join(BytesToStrings(words), "|"))
+		})
+	}
+}
+
+func Test_DetectLang_MaximalMatching(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"ประเทศไทยมีนักท่องเที่ยวมาก", "th"},
+		{"ປະເທດລາວມີນັກທ່ອງທ່ຽວຫຼາຍ", "lo"},
+		{"ខ្ញុំនៅភ្នំពេញ", "km"},
+		{"မြန်မာနိုင်ငံသည်ကမ္ဘာ၏ခရီးသွား", "my"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			cfg := New()
+			DetectLang(cfg, tt.input)
+			assert.Equal(t, tt.expected, cfg.Lang)
+			assert.True(t, NeedsMaximalMatching(cfg.Lang))
+			assert.True(t, cfg.StopWords.IsStopWord(string(cfg.Segment([]byte(tt.input))[1])))
+		})
+	}
+}
+
+func Test_dictCache(t *testing.T) {
+	dict := filepath.Join(t.TempDir(), "brands.txt")
+	cache := newDictCache[int]()
+	var loads int
+	load := func() (int, error) {
+		loads++
+		return loads, checkDict(dict)
+	}
+
+	// failures are cached until the dictionary is changed
+	_, err := cache.load("de", []string{dict}, load)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	_, err = cache.load("de", []string{dict}, load)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.Equal(t, 1, loads)
+
+	assert.Nil(t, os.WriteFile(dict, []byte("foo\n"), 0644))
+	v, err := cache.load("de", []string{dict}, load)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, v)
+	v, err = cache.load("de", []string{dict}, load)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, v)
+	assert.Len(t, cache.entries, 1)
+}
@@ -0,0 +1,36 @@
+package config
+
+// stop words of the languages, which aren't provided by the github.com/zoomio/stopwords,
+// stop words of Thai, Lao, Khmer & Burmese are also the dictionary words of the maximal matching (see NeedsMaximalMatching)
+var extraStopWords = map[string][]string{
+	"nl": {
+		"de", "en", "van", "ik", "te", "dat", "die", "in", "een", "hij", "het", "niet", "zijn", "is", "was",
+		"op", "aan", "met", "als", "voor", "had", "er", "maar", "om", "hem", "dan", "zou", "of", "wat", "mijn",
+		"men", "dit", "zo", "door", "over", "ze", "zich", "bij", "ook", "tot", "je", "mij", "uit", "der", "daar",
+		"haar", "naar", "heb", "hoe", "heeft", "hebben", "deze", "u", "want", "nog", "zal", "me", "zij", "nu",
+		"ge", "geen", "omdat", "iets", "worden", "toch", "al", "waren", "veel", "meer", "doen", "toen", "moet",
+		"ben", "zonder", "kan", "hun", "dus", "alles", "onder", "ja", "eens", "hier", "wie", "werd", "altijd",
+		"doch", "wordt", "wezen", "kunnen", "ons", "zelf", "tegen", "na", "reeds", "wil", "kon", "niets", "uw",
+		"iemand", "geweest", "andere", "wij", "we", "sinds", "tussen",
+	},
+	"th": {
+		"ที่", "และ", "ใน", "ของ", "เป็น", "ได้", "มี", "จะ", "ไม่", "ให้", "กับ", "ว่า", "แต่", "หรือ", "ก็",
+		"ไป", "มา", "แล้ว", "นี้", "นั้น", "โดย", "จาก", "ถึง", "อยู่", "ซึ่ง", "เพื่อ", "ยัง", "คือ", "ทั้ง",
+		"อีก", "ต้อง", "กัน", "ด้วย", "ไว้", "ขึ้น", "ออก", "เมื่อ", "หาก", "ถ้า", "เพราะ", "ทำ", "อย่าง",
+		"การ", "ความ", "ครับ", "ค่ะ",
+	},
+	"lo": {
+		"ທີ່", "ແລະ", "ໃນ", "ຂອງ", "ເປັນ", "ໄດ້", "ມີ", "ຈະ", "ບໍ່", "ໃຫ້", "ກັບ", "ວ່າ", "ແຕ່", "ຫຼື", "ກໍ",
+		"ໄປ", "ມາ", "ແລ້ວ", "ນີ້", "ນັ້ນ", "ໂດຍ", "ຈາກ", "ເຖິງ", "ຢູ່", "ຊຶ່ງ", "ເພື່ອ", "ຍັງ", "ຄື", "ອີກ",
+		"ຕ້ອງ", "ກັນ", "ດ້ວຍ", "ການ", "ຄວາມ",
+	},
+	"km": {
+		"និង", "នៅ", "ក្នុង", "របស់", "គឺ", "ជា", "បាន", "មាន", "នឹង", "មិន", "ឲ្យ", "ជាមួយ", "ថា", "ប៉ុន្តែ",
+		"ឬ", "ក៏", "ទៅ", "មក", "ហើយ", "នេះ", "នោះ", "ដោយ", "ពី", "ដល់", "ដែល", "ដើម្បី", "ទៀត", "ត្រូវ",
+		"គ្នា", "ផង", "ការ", "សេចក្តី",
+	},
+	"my": {
+		"သည်", "ကို", "၏", "နှင့်", "တွင်", "မှာ", "က", "ပါ", "တယ်", "ဖြစ်", "ရှိ", "မ", "ရ", "များ", "သော",
+		"ထို", "ဒီ", "ဤ", "လည်း", "ပြီး", "နဲ့", "နေ", "သို့", "မှ", "ရန်", "အတွက်", "ကြောင့်", "ခဲ့", "သည့်",
+	},
+}
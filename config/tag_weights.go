@@ -1,9 +1,12 @@
 package config
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strconv"
 	"strings"
 )
@@ -20,33 +23,88 @@ type TagWeightsType byte
 // TagWeights ...
 type TagWeights map[string]float64
 
+// MergeTagWeights returns new tag weights with the extra weights added on top of the given ones,
+// none of the given weights are modified.
+func MergeTagWeights(weights, extra TagWeights) TagWeights {
+	res := make(TagWeights, len(weights)+len(extra))
+	for k, v := range weights {
+		res[k] = v
+	}
+	for k, v := range extra {
+		res[k] = v
+	}
+	return res
+}
+
+// ParseTagWeights reads tag weights skipping malformed entries, use ReadTagWeights to get errors.
 func ParseTagWeights(reader io.Reader, readerType TagWeightsType) TagWeights {
+	weights, _ := ReadTagWeights(reader, readerType)
+	return weights
+}
+
+// ReadTagWeights reads tag weights of the given type, malformed entries are skipped
+// and reported with the *TagWeightsError along with the rest of the weights.
+func ReadTagWeights(reader io.Reader, readerType TagWeightsType) (TagWeights, error) {
 	weights := TagWeights{}
 
 	switch readerType {
 	case String:
 		buf := new(strings.Builder)
 		if _, err := io.Copy(buf, reader); err != nil {
-			println(fmt.Errorf("error: can't read string: %w", err))
+			return weights, &TagWeightsError{Name: "tag weights", Err: err}
 		}
+		var malformed []string
 		for _, v := range strings.Split(buf.String(), "|") {
+			if strings.TrimSpace(v) == "" {
+				continue
+			}
 			tuple := strings.Split(v, ":")
-			if len(tuple) != 2 {
+			if len(tuple) != 2 || tuple[0] == "" {
+				malformed = append(malformed, v)
 				continue
 			}
 			f, err := strconv.ParseFloat(tuple[1], 64)
 			if err != nil {
-				println(fmt.Errorf("error: can't read score for [%s]: %w", tuple[0], err))
+				malformed = append(malformed, v)
+				continue
 			}
 			weights[tuple[0]] = f
 		}
+		if len(malformed) > 0 {
+			return weights, &TagWeightsError{Name: "tag weights", Entries: malformed}
+		}
 	case JSON:
 		if err := json.NewDecoder(reader).Decode(&weights); err != nil {
-			println(fmt.Errorf("error: can't read JSON: %w", err))
+			return TagWeights{}, &TagWeightsError{Name: "tag weights", Err: err}
 		}
 	default:
-		fmt.Printf("error: unknown readerType\n")
+		return weights, &TagWeightsError{Name: "tag weights", Err: fmt.Errorf("unknown type %d", readerType)}
 	}
 
-	return weights
+	return weights, nil
+}
+
+// readTagWeights reads tag weights from the string or from the file
+// and names errors after the given name of the option.
+func readTagWeights(name, v string, readerType TagWeightsType) (TagWeights, error) {
+	var weights TagWeights
+	var err error
+	var file string
+	if readerType == JSON {
+		file = v
+		var f *os.File
+		if f, err = os.Open(v); err != nil {
+			return nil, &TagWeightsError{Name: name, Source: file, Err: err}
+		}
+		defer f.Close()
+		weights, err = ReadTagWeights(bufio.NewReader(f), JSON)
+	} else {
+		weights, err = ReadTagWeights(strings.NewReader(v), readerType)
+	}
+	var twErr *TagWeightsError
+	if errors.As(err, &twErr) {
+		twErr.Name = name
+		twErr.Source = file
+	}
+	return weights, err
 }
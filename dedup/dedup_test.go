@@ -0,0 +1,82 @@
+package dedup
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/model"
+)
+
+const article = `the central bank raised interest rates by a quarter point on wednesday
+citing persistent inflation in services and a tight labour market while signalling
+that further increases would depend on incoming data about wages prices and growth`
+
+func sign(text string) *model.Meta {
+	s := NewSigner()
+	s.Add(strings.Fields(text)...)
+	meta := &model.Meta{}
+	s.Sign(meta)
+	return meta
+}
+
+func Test_Signer(t *testing.T) {
+	original := sign(article)
+	syndicated := sign("read more news " + article + " subscribe to our newsletter")
+	unrelated := sign(`the recipe calls for dark chocolate butter sugar and eggs which are whisked
+until pale then folded with flour and baked for twenty minutes until the top cracks`)
+
+	assert.Len(t, original.MinHash, MinHashSize)
+	assert.Equal(t, original, sign(article))
+
+	assert.Greater(t, MinHashSimilarity(original.MinHash, syndicated.MinHash), 0.6)
+	assert.Less(t, MinHashSimilarity(original.MinHash, unrelated.MinHash), 0.1)
+
+	assert.Less(t, SimHashDistance(original.SimHash, syndicated.SimHash), SimHashDistance(original.SimHash, unrelated.SimHash))
+	assert.Equal(t, 1.0, SimHashSimilarity(original.SimHash, original.SimHash))
+
+	assert.Greater(t, Similarity(original, syndicated), Similarity(original, unrelated))
+	assert.Greater(t, Similarity(&model.Meta{SimHash: original.SimHash}, &model.Meta{SimHash: syndicated.SimHash}), 0.8)
+
+	// documents shorter than a shingle are signed too
+	assert.Len(t, sign("chocolate").MinHash, MinHashSize)
+	assert.Nil(t, sign("").MinHash)
+
+	// nil signer does nothing
+	var s *Signer
+	s.Add("chocolate")
+	s.Sign(&model.Meta{})
+}
+
+func Test_Index(t *testing.T) {
+	idx := NewIndex(0.5)
+	assert.Nil(t, idx.Add("original", sign(article)))
+	assert.Nil(t, idx.Add("syndicated", sign("read more news "+article+" subscribe to our newsletter")))
+	for i := 0; i < 20; i++ {
+		assert.Nil(t, idx.Add(fmt.Sprintf("other-%d", i), sign(fmt.Sprintf("completely different story number %d about gardening tomatoes and weather", i))))
+	}
+	assert.Equal(t, 22, idx.Len())
+	assert.ErrorIs(t, idx.Add("empty", &model.Meta{}), ErrNoSignature)
+
+	matches := idx.DuplicatesOf("original")
+	assert.Len(t, matches, 1)
+	assert.Equal(t, "syndicated", matches[0].ID)
+
+	matches, err := idx.Duplicates(sign(article + " updated"))
+	assert.Nil(t, err)
+	assert.Equal(t, "original", matches[0].ID)
+
+	_, err = idx.Duplicates(&model.Meta{})
+	assert.ErrorIs(t, err, ErrNoSignature)
+	assert.Nil(t, idx.DuplicatesOf("missing"))
+
+	idx.Remove("syndicated")
+	assert.Empty(t, idx.DuplicatesOf("original"))
+	assert.Equal(t, 21, idx.Len())
+
+	// re-adding replaces the document
+	assert.Nil(t, idx.Add("original", sign(article)))
+	assert.Equal(t, 21, idx.Len())
+}
@@ -0,0 +1,163 @@
+package dedup
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	"github.com/zoomio/tagify/model"
+)
+
+const (
+	// bands * rows = MinHashSize, documents with similarity of ~0.4
+	// have 50% chance to become candidates, with ~0.7 - 99%
+	bands = 32
+	rows  = MinHashSize / bands
+)
+
+// ErrNoSignature is returned for documents without MinHash signature (see config.Signatures).
+var ErrNoSignature = errors.New("document has no MinHash signature")
+
+// Match is a near-duplicate found in the index.
+type Match struct {
+	ID         string
+	Similarity float64 // estimated Jaccard similarity
+}
+
+// Index finds near-duplicates among many documents using locality-sensitive hashing
+// of their MinHash signatures, it is safe for concurrent use.
+type Index struct {
+	threshold float64
+
+	mu      sync.RWMutex
+	docs    map[string][]uint64
+	buckets []map[uint64][]string // bucket of the band -> IDs
+}
+
+// NewIndex creates empty index, documents with similarity
+// of at least the threshold (0.0 to 1.0) are near-duplicates.
+func NewIndex(threshold float64) *Index {
+	buckets := make([]map[uint64][]string, bands)
+	for i := range buckets {
+		buckets[i] = map[uint64][]string{}
+	}
+	return &Index{threshold: threshold, docs: map[string][]uint64{}, buckets: buckets}
+}
+
+// Add puts document with the given ID into the index.
+func (idx *Index) Add(id string, meta *model.Meta) error {
+	if meta == nil || len(meta.MinHash) != MinHashSize {
+		return ErrNoSignature
+	}
+	sig := append([]uint64(nil), meta.MinHash...)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if _, ok := idx.docs[id]; ok {
+		idx.remove(id)
+	}
+	idx.docs[id] = sig
+	for b, key := range bandKeys(sig) {
+		idx.buckets[b][key] = append(idx.buckets[b][key], id)
+	}
+	return nil
+}
+
+// Remove deletes document with the given ID from the index.
+func (idx *Index) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+}
+
+func (idx *Index) remove(id string) {
+	sig, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for b, key := range bandKeys(sig) {
+		ids := idx.buckets[b][key]
+		for i, v := range ids {
+			if v == id {
+				ids = append(ids[:i], ids[i+1:]...)
+				break
+			}
+		}
+		if len(ids) == 0 {
+			delete(idx.buckets[b], key)
+		} else {
+			idx.buckets[b][key] = ids
+		}
+	}
+	delete(idx.docs, id)
+}
+
+// Len returns number of the documents in the index.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Duplicates returns near-duplicates of the document, the most similar go first.
+func (idx *Index) Duplicates(meta *model.Meta) ([]Match, error) {
+	if meta == nil || len(meta.MinHash) != MinHashSize {
+		return nil, ErrNoSignature
+	}
+	return idx.duplicates(meta.MinHash, ""), nil
+}
+
+// DuplicatesOf returns near-duplicates of the indexed document with the given ID,
+// the document itself is excluded, nil is returned if there is no such document.
+func (idx *Index) DuplicatesOf(id string) []Match {
+	idx.mu.RLock()
+	sig, ok := idx.docs[id]
+	idx.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return idx.duplicates(sig, id)
+}
+
+func (idx *Index) duplicates(sig []uint64, skip string) []Match {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	seen := map[string]bool{skip: true}
+	matches := []Match{}
+	for b, key := range bandKeys(sig) {
+		for _, id := range idx.buckets[b][key] {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if s := MinHashSimilarity(sig, idx.docs[id]); s >= idx.threshold {
+				matches = append(matches, Match{ID: id, Similarity: s})
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Similarity != matches[j].Similarity {
+			return matches[i].Similarity > matches[j].Similarity
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	return matches
+}
+
+func bandKeys(sig []uint64) []uint64 {
+	keys := make([]uint64, bands)
+	buf := make([]byte, 8)
+	for b := 0; b < bands; b++ {
+		h := fnv.New64a()
+		for _, v := range sig[b*rows : (b+1)*rows] {
+			binary.LittleEndian.PutUint64(buf, v)
+			_, _ = h.Write(buf)
+		}
+		keys[b] = h.Sum64()
+	}
+	return keys
+}
@@ -0,0 +1,159 @@
+// Package dedup finds near-duplicate documents by their signatures.
+//
+// Unlike Meta.DocHash, which changes completely with any edit, SimHash & MinHash
+// signatures of similar documents are similar. Both are computed over shingles,
+// i.e. overlapping sequences of ShingleSize tokens, see Signer.
+package dedup
+
+import (
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"strings"
+
+	"github.com/zoomio/tagify/model"
+)
+
+const (
+	// ShingleSize is the number of consecutive tokens in a shingle.
+	ShingleSize = 3
+	// MinHashSize is the number of hash functions (i.e. values) of MinHash signature.
+	MinHashSize = 128
+)
+
+// MinHash permutations are h(x) = a*x + b (mod 2^64), with a being odd.
+var minHashA, minHashB = permutations(MinHashSize)
+
+func permutations(n int) (a, b []uint64) {
+	// splitmix64 with a fixed seed, so signatures are stable between runs
+	seed := uint64(0x5eed)
+	next := func() uint64 {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		return z ^ (z >> 31)
+	}
+	a, b = make([]uint64, n), make([]uint64, n)
+	for i := 0; i < n; i++ {
+		a[i] = next() | 1
+		b[i] = next()
+	}
+	return a, b
+}
+
+// Signer computes signatures of the stream of tokens with bounded memory,
+// methods of nil Signer do nothing, so it could be used unconditionally.
+type Signer struct {
+	window   []string
+	shingles int
+	weights  [64]int
+	mins     []uint64
+}
+
+// NewSigner creates new instance of Signer.
+func NewSigner() *Signer {
+	s := &Signer{
+		window: make([]string, 0, ShingleSize),
+		mins:   make([]uint64, MinHashSize),
+	}
+	for i := range s.mins {
+		s.mins[i] = math.MaxUint64
+	}
+	return s
+}
+
+// Add adds next token of the document.
+func (s *Signer) Add(tokens ...string) {
+	if s == nil {
+		return
+	}
+	for _, t := range tokens {
+		if len(s.window) == ShingleSize {
+			copy(s.window, s.window[1:])
+			s.window = s.window[:ShingleSize-1]
+		}
+		s.window = append(s.window, t)
+		if len(s.window) == ShingleSize {
+			s.addShingle()
+		}
+	}
+}
+
+func (s *Signer) addShingle() {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strings.Join(s.window, " ")))
+	x := h.Sum64()
+
+	s.shingles++
+	for i := range s.weights {
+		if x&(1<<uint(i)) != 0 {
+			s.weights[i]++
+		} else {
+			s.weights[i]--
+		}
+	}
+	for i := range s.mins {
+		if v := minHashA[i]*x + minHashB[i]; v < s.mins[i] {
+			s.mins[i] = v
+		}
+	}
+}
+
+// Sign sets SimHash & MinHash signatures of the added tokens in the meta,
+// documents shorter than a shingle are signed by all of their tokens.
+func (s *Signer) Sign(meta *model.Meta) {
+	if s == nil || meta == nil {
+		return
+	}
+	if s.shingles == 0 {
+		if len(s.window) == 0 {
+			return
+		}
+		s.addShingle()
+	}
+
+	var sim uint64
+	for i, w := range s.weights {
+		if w > 0 {
+			sim |= 1 << uint(i)
+		}
+	}
+	meta.SimHash = sim
+	meta.MinHash = append([]uint64(nil), s.mins...)
+}
+
+// SimHashDistance returns Hamming distance of the SimHash signatures,
+// i.e. number of different bits (0 to 64).
+func SimHashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// SimHashSimilarity returns similarity (0.0 to 1.0) of the SimHash signatures.
+func SimHashSimilarity(a, b uint64) float64 {
+	return 1 - float64(SimHashDistance(a, b))/64
+}
+
+// MinHashSimilarity returns estimated Jaccard similarity (0.0 to 1.0) of the shingles
+// of the documents, 0 is returned for signatures of different sizes.
+func MinHashSimilarity(a, b []uint64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var same int
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(a))
+}
+
+// Similarity returns similarity of the documents by their MinHash signatures
+// or by SimHash signatures if there are no MinHash ones.
+func Similarity(a, b *model.Meta) float64 {
+	if len(a.MinHash) > 0 && len(b.MinHash) > 0 {
+		return MinHashSimilarity(a.MinHash, b.MinHash)
+	}
+	return SimHashSimilarity(a.SimHash, b.SimHash)
+}
@@ -0,0 +1,101 @@
+// Package diff compares tags of two documents, e.g. two versions of the same page.
+//
+// Scores are compared as weights of the normalized vectors of all the found tags of the results
+// (see similarity.NewRawVector), i.e. as shares of the topical focus of the documents,
+// which makes documents of different length comparable. Limit of the ranked tags (Result.Tags)
+// doesn't affect comparison, e.g. tag, which only drops below the limit, isn't lost.
+package diff
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/zoomio/tagify/model"
+	"github.com/zoomio/tagify/similarity"
+)
+
+// DefaultThreshold is the default minimal change of the weight to be significant.
+const DefaultThreshold = 0.1
+
+// Change is a change of the tag weight between the documents.
+type Change struct {
+	Value  string
+	Before float64 // weight in the first document, 0 if tag is gained
+	After  float64 // weight in the second document, 0 if tag is lost
+	Delta  float64 // After - Before
+}
+
+// Report is the outcome of comparison of two documents.
+type Report struct {
+	// Gained tags are found only in the second document.
+	Gained []*Change
+	// Lost tags are found only in the first document.
+	Lost []*Change
+	// Changed tags are found in both documents with significantly different weights.
+	Changed []*Change
+	// Unchanged is the number of tags found in both documents with insignificant changes.
+	Unchanged int
+	// Similarity is the cosine similarity of the documents.
+	Similarity float64
+}
+
+// Option allows to customise comparison.
+type Option func(*options)
+
+type options struct {
+	threshold float64
+}
+
+// Threshold sets minimal absolute change of the weight to be significant (see DefaultThreshold).
+func Threshold(v float64) Option {
+	return func(o *options) {
+		o.threshold = v
+	}
+}
+
+// Compare compares tags of the results a (e.g. before) & b (e.g. after).
+func Compare(a, b *model.Result, opts ...Option) *Report {
+	o := &options{threshold: DefaultThreshold}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	va := similarity.NewRawVector(a).Normalize()
+	vb := similarity.NewRawVector(b).Normalize()
+
+	r := &Report{Similarity: va.Dot(vb)}
+	for k, before := range va {
+		after, ok := vb[k]
+		c := &Change{Value: k, Before: before, After: after, Delta: after - before}
+		switch {
+		case !ok:
+			r.Lost = append(r.Lost, c)
+		case math.Abs(c.Delta) >= o.threshold:
+			r.Changed = append(r.Changed, c)
+		default:
+			r.Unchanged++
+		}
+	}
+	for k, after := range vb {
+		if _, ok := va[k]; !ok {
+			r.Gained = append(r.Gained, &Change{Value: k, After: after, Delta: after})
+		}
+	}
+
+	sortChanges(r.Gained)
+	sortChanges(r.Lost)
+	sortChanges(r.Changed)
+	return r
+}
+
+// sortChanges puts the biggest changes first.
+func sortChanges(cs []*Change) {
+	sort.Slice(cs, func(i, j int) bool {
+		di, dj := math.Abs(cs[i].Delta), math.Abs(cs[j].Delta)
+		if di != dj {
+			return di > dj
+		}
+		return strings.Compare(cs[i].Value, cs[j].Value) < 0
+	})
+}
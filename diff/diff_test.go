@@ -0,0 +1,61 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/internal/testutil"
+	"github.com/zoomio/tagify/model"
+)
+
+func values(cs []*Change) []string {
+	out := make([]string, len(cs))
+	for i, c := range cs {
+		out[i] = c.Value
+	}
+	return out
+}
+
+func Test_Compare(t *testing.T) {
+	before := testutil.NewResult(map[string]float64{"chocolate": 4, "cocoa": 2, "milk": 2, "sugar": 1})
+	after := testutil.NewResult(map[string]float64{"chocolate": 2, "cocoa": 2, "milk": 2, "beans": 3, "fair": 1})
+
+	r := Compare(before, after)
+	assert.Equal(t, []string{"beans", "fair"}, values(r.Gained))
+	assert.Equal(t, []string{"sugar"}, values(r.Lost))
+	assert.Equal(t, []string{"chocolate"}, values(r.Changed))
+	assert.Less(t, r.Changed[0].Delta, 0.0)
+	assert.InDelta(t, r.Changed[0].After-r.Changed[0].Before, r.Changed[0].Delta, 0.0001)
+	assert.Equal(t, 2, r.Unchanged)
+	assert.Greater(t, r.Similarity, 0.5)
+	assert.Less(t, r.Similarity, 1.0)
+
+	// everything is significant
+	r = Compare(before, after, Threshold(0))
+	assert.Len(t, r.Changed, 3)
+	assert.Equal(t, 0, r.Unchanged)
+
+	r = Compare(before, before)
+	assert.Empty(t, r.Gained)
+	assert.Empty(t, r.Lost)
+	assert.Empty(t, r.Changed)
+	assert.InDelta(t, 1, r.Similarity, 0.0001)
+
+	r = Compare(model.EmptyResult(), before)
+	assert.Len(t, r.Gained, 4)
+	assert.Equal(t, 0.0, r.Similarity)
+}
+
+func Test_Compare_Limit(t *testing.T) {
+	// "milk" drops just past the limit of the ranked tags, but is still found in the document
+	before := testutil.NewResult(map[string]float64{"chocolate": 4, "milk": 3, "cocoa": 2.9})
+	before.Tags = []*model.Tag{before.RawTags["chocolate"], before.RawTags["milk"]}
+	after := testutil.NewResult(map[string]float64{"chocolate": 4, "milk": 2.9, "cocoa": 3})
+	after.Tags = []*model.Tag{after.RawTags["chocolate"], after.RawTags["cocoa"]}
+
+	r := Compare(before, after)
+	assert.Empty(t, r.Lost)
+	assert.Empty(t, r.Gained)
+	assert.Equal(t, 3, r.Unchanged)
+}
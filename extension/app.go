@@ -1,12 +1,17 @@
 package extension
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -14,9 +19,11 @@ import (
 )
 
 const (
-	install_path_prefix = "$HOME/bin/tagify_"
+	installDir        = "bin"
+	installFilePrefix = "tagify_"
 
 	flagVersion = "-version"
+	flagExt     = "-ext"
 )
 
 // App represents the executable for the extension.
@@ -25,7 +32,15 @@ type App struct {
 	source      string
 	file        string
 	version     string
+	checksum    string
+	installDir  string
 	execTimeout time.Duration
+
+	// verification
+	expectedChecksum string
+	requireChecksum  bool
+	signature        []byte
+	trustedKeys      []ed25519.PublicKey
 }
 
 // New creates new instance of App based on the provided parameters.
@@ -39,6 +54,89 @@ func New(name, source string) *App {
 	}
 }
 
+// Load creates new instance of App based on the already installed executable.
+func Load(name, file, version string) *App {
+	a := New(name, "")
+	a.file = file
+	a.version = version
+	return a
+}
+
+// FromManifest creates new instance of App based on the manifest entry of the installed App.
+func FromManifest(e *ManifestEntry) *App {
+	a := Load(e.Name, e.Path, e.Version)
+	a.source = e.Source
+	a.checksum = e.Checksum
+	return a
+}
+
+// ManifestEntry returns manifest entry of the installed App.
+func (a *App) ManifestEntry() *ManifestEntry {
+	return &ManifestEntry{
+		Name:     a.name,
+		Source:   a.source,
+		Version:  a.version,
+		Checksum: a.checksum,
+		Path:     a.file,
+	}
+}
+
+// Name returns name of the App.
+func (a *App) Name() string {
+	return a.name
+}
+
+// Version returns version of the App.
+func (a *App) Version() string {
+	return a.version
+}
+
+// Source returns source of the App.
+func (a *App) Source() string {
+	return a.source
+}
+
+// File returns path to the executable of the App.
+func (a *App) File() string {
+	return a.file
+}
+
+// Checksum returns SHA-256 checksum (hex) of the executable of the App.
+func (a *App) Checksum() string {
+	return a.checksum
+}
+
+// SetExecTimeout sets the deadline for a single execution of the App.
+func (a *App) SetExecTimeout(d time.Duration) {
+	a.execTimeout = d
+}
+
+// SetChecksum sets expected SHA-256 checksum (hex) of the App, install fails on mismatch.
+func (a *App) SetChecksum(checksum string) {
+	a.expectedChecksum = checksum
+}
+
+// RequireChecksum makes install to fail if expected checksum is not set.
+func (a *App) RequireChecksum(v bool) {
+	a.requireChecksum = v
+}
+
+// SetSignature sets ed25519 signature of the App, which is verified with the trusted keys on install.
+func (a *App) SetSignature(sig []byte) {
+	a.signature = sig
+}
+
+// SetTrustedKeys sets ed25519 public keys, which App signature is verified with,
+// if there are trusted keys, then install fails without valid signature.
+func (a *App) SetTrustedKeys(keys []ed25519.PublicKey) {
+	a.trustedKeys = keys
+}
+
+// SetInstallDir sets the directory to install App into, "$HOME/bin" is used by default.
+func (a *App) SetInstallDir(dir string) {
+	a.installDir = dir
+}
+
 // Install installs the App.
 func (a *App) Install(ctx context.Context) error {
 	if a.source == "" {
@@ -58,17 +156,25 @@ func (a *App) Install(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to download source %q: %w", a.source, err)
 	}
-	fName := toFileName(u.Hostname() + "/" + u.Path)
+	if err = a.verify(bs); err != nil {
+		return fmt.Errorf("failed to verify %q: %w", a.name, err)
+	}
+	dir, err := a.dir()
+	if err != nil {
+		return fmt.Errorf("failed to install %q: %w", a.name, err)
+	}
+	fName := filepath.Join(dir, toFileName(u.Hostname()+"/"+u.Path))
 	err = os.WriteFile(fName, bs, os.FileMode(0755))
 	if err != nil {
 		return fmt.Errorf("failed to save file %q: %w", fName, err)
 	}
 	a.file = fName
+	a.checksum = Checksum(bs)
 	bs, err = a.Run(ctx, flagVersion)
 	if err != nil {
 		return fmt.Errorf("failed to install %q: %w", a.name, err)
 	}
-	a.version = string(bs)
+	a.version = strings.TrimSpace(string(bs))
 	fmt.Printf("extension %q [%s] has been successfully installed to %q.\n", a.name, a.version, a.file)
 	return nil
 }
@@ -81,8 +187,71 @@ func (a *App) Run(ctx context.Context, args ...string) ([]byte, error) {
 	return cmd.Output()
 }
 
+// Call executes the App according to the extension protocol,
+// request is written into the STDIN of the App and response is read from its STDOUT.
+func (a *App) Call(ctx context.Context, req *Request) (*Response, error) {
+	req.Protocol = ProtocolVersion
+	in, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request for %q: %w", a.name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.execTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, a.file, flagExt)
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("%q timed out after %v on %q hook", a.name, a.execTimeout, req.Hook)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %q on %q hook: %w: %s", a.name, req.Hook, err, strings.TrimSpace(stderr.String()))
+	}
+
+	resp := &Response{}
+	if err = json.Unmarshal(out, resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response of %q on %q hook: %w", a.name, req.Hook, err)
+	}
+	if resp.Error != "" {
+		return resp, fmt.Errorf("%q failed on %q hook: %s", a.name, req.Hook, resp.Error)
+	}
+
+	return resp, nil
+}
+
+// Uninstall removes the executable of the App.
+func (a *App) Uninstall() error {
+	if a.file == "" {
+		return fmt.Errorf("%q is not installed", a.name)
+	}
+	if err := os.Remove(a.file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove file %q: %w", a.file, err)
+	}
+	a.file = ""
+	return nil
+}
+
+func (a *App) dir() (string, error) {
+	dir := a.installDir
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, installDir)
+	}
+	if err := os.MkdirAll(dir, os.FileMode(0755)); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
 func toFileName(s string) string {
 	fName := strings.Replace(s, ".", "-", -1)
 	fName = strings.Replace(fName, "/", "_", -1)
-	return install_path_prefix + fName
+	return installFilePrefix + fName
 }
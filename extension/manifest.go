@@ -0,0 +1,103 @@
+package extension
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	manifestDir  = ".tagify"
+	manifestFile = "extensions.json"
+)
+
+// ManifestEntry describes installed extension App.
+type ManifestEntry struct {
+	Name        string    `json:"name"`
+	Source      string    `json:"source"`
+	Version     string    `json:"version"`
+	Checksum    string    `json:"checksum"`
+	Path        string    `json:"path"`
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// Manifest keeps track of the installed extension Apps.
+type Manifest struct {
+	path       string
+	Extensions map[string]*ManifestEntry `json:"extensions"`
+}
+
+// DefaultManifestPath returns location of the manifest, which is "$HOME/.tagify/extensions.json".
+func DefaultManifestPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, manifestDir, manifestFile), nil
+}
+
+// LoadManifest reads manifest from the given path, empty manifest is returned if there is no file.
+func LoadManifest(path string) (*Manifest, error) {
+	m := &Manifest{path: path, Extensions: map[string]*ManifestEntry{}}
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return m, nil
+		}
+		return nil, fmt.Errorf("failed to read manifest %q: %w", path, err)
+	}
+	if err = json.Unmarshal(bs, m); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest %q: %w", path, err)
+	}
+	if m.Extensions == nil {
+		m.Extensions = map[string]*ManifestEntry{}
+	}
+	return m, nil
+}
+
+// Save writes manifest into its file.
+func (m *Manifest) Save() error {
+	if err := os.MkdirAll(filepath.Dir(m.path), os.FileMode(0755)); err != nil {
+		return fmt.Errorf("failed to create directory for manifest %q: %w", m.path, err)
+	}
+	bs, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest %q: %w", m.path, err)
+	}
+	if err = os.WriteFile(m.path, bs, os.FileMode(0644)); err != nil {
+		return fmt.Errorf("failed to save manifest %q: %w", m.path, err)
+	}
+	return nil
+}
+
+// Get returns entry of the installed App by its name.
+func (m *Manifest) Get(name string) (*ManifestEntry, bool) {
+	e, ok := m.Extensions[name]
+	return e, ok
+}
+
+// Put adds or replaces entry of the installed App.
+func (m *Manifest) Put(e *ManifestEntry) {
+	m.Extensions[e.Name] = e
+}
+
+// Remove removes entry of the installed App.
+func (m *Manifest) Remove(name string) {
+	delete(m.Extensions, name)
+}
+
+// List returns entries of all installed Apps sorted by name.
+func (m *Manifest) List() []*ManifestEntry {
+	list := make([]*ManifestEntry, 0, len(m.Extensions))
+	for _, v := range m.Extensions {
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
@@ -0,0 +1,63 @@
+package extension
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testAppScript = "#!/bin/sh\necho v1.2.3\n"
+
+func Test_Manifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ext", "extensions.json")
+
+	m, err := LoadManifest(path)
+	assert.Nil(t, err)
+	assert.Len(t, m.List(), 0)
+
+	m.Put(&ManifestEntry{Name: "foo", Version: "v0.0.1"})
+	m.Put(&ManifestEntry{Name: "bar", Version: "v0.0.2"})
+	assert.Nil(t, m.Save())
+
+	m, err = LoadManifest(path)
+	assert.Nil(t, err)
+	list := m.List()
+	assert.Len(t, list, 2)
+	assert.Equal(t, "bar", list[0].Name)
+	assert.Equal(t, "foo", list[1].Name)
+
+	m.Remove("foo")
+	_, ok := m.Get("foo")
+	assert.False(t, ok)
+	e, ok := m.Get("bar")
+	assert.True(t, ok)
+	assert.Equal(t, "v0.0.2", e.Version)
+}
+
+func Test_App_Install(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "app.sh")
+	assert.Nil(t, os.WriteFile(src, []byte(testAppScript), os.FileMode(0755)))
+
+	app := New("test-app", src)
+	app.SetInstallDir(filepath.Join(dir, "bin"))
+	assert.Nil(t, app.Install(context.TODO()))
+	assert.Equal(t, "v1.2.3", app.Version())
+	assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256([]byte(testAppScript))), app.Checksum())
+	assert.FileExists(t, app.File())
+	assert.Equal(t, filepath.Join(dir, "bin"), filepath.Dir(app.File()))
+
+	e := app.ManifestEntry()
+	assert.Equal(t, "test-app", e.Name)
+	assert.Equal(t, src, e.Source)
+	assert.Equal(t, app.Checksum(), e.Checksum)
+
+	loaded := FromManifest(e)
+	assert.Nil(t, loaded.Uninstall())
+	assert.NoFileExists(t, e.Path)
+}
@@ -0,0 +1,41 @@
+package extension
+
+// ProtocolVersion is the version of the protocol,
+// which is used for communication with the extension Apps.
+const ProtocolVersion = "1"
+
+// Protocol hooks.
+const (
+	// HookPreRank is called with all tags found in the contents, before ranking.
+	HookPreRank = "pre_rank"
+	// HookPostRank is called with the ranked tags.
+	HookPostRank = "post_rank"
+)
+
+// ProtoTag is a tag as it is exchanged with the extension Apps.
+type ProtoTag struct {
+	Value     string  `json:"value"`
+	Score     float64 `json:"score"`
+	Count     int     `json:"count"`
+	Docs      int     `json:"docs"`
+	DocsCount int     `json:"docs_count"`
+	Entity    string  `json:"entity,omitempty"` // kind of the named entity, e.g. "name"
+}
+
+// Request is written as JSON into the STDIN of the extension App.
+type Request struct {
+	Protocol    string      `json:"protocol"`
+	Hook        string      `json:"hook"`
+	Source      string      `json:"source,omitempty"`
+	ContentType string      `json:"content_type,omitempty"`
+	Lang        string      `json:"lang,omitempty"`
+	Tags        []*ProtoTag `json:"tags"`
+}
+
+// Response is read as JSON from the STDOUT of the extension App.
+type Response struct {
+	// Tags replace the tags sent in the request, tags are left unchanged if omitted.
+	Tags  []*ProtoTag            `json:"tags,omitempty"`
+	Data  map[string]interface{} `json:"data,omitempty"`
+	Error string                 `json:"error,omitempty"`
+}
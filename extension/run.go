@@ -0,0 +1,136 @@
+package extension
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+type runKey struct{}
+
+// run collects results of the extensions during a single run.
+type run struct {
+	id      uint64
+	mu      sync.Mutex
+	results map[string]*ExtResult // by name & version
+}
+
+var runIDs atomic.Uint64
+
+// WithRun returns context of a new run, in which results of the extensions are collected
+// (see RunResult & RunResults), context of a run is returned as is.
+func WithRun(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(runKey{}).(*run); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, runKey{}, &run{id: runIDs.Add(1), results: map[string]*ExtResult{}})
+}
+
+// RunID returns identifier of the run of the given context, 0 if there is none.
+func RunID(ctx context.Context) uint64 {
+	if r, ok := ctx.Value(runKey{}).(*run); ok {
+		return r.id
+	}
+	return 0
+}
+
+// RunResults returns results of the given extensions collected in the run of the given context,
+// results of the extensions, which aren't collected per run (see PerRun), are taken from the extensions.
+func RunResults(ctx context.Context, exts []Extension) map[string]map[string]*ExtResult {
+	r, ok := ctx.Value(runKey{}).(*run)
+	if !ok {
+		return MapResults(exts)
+	}
+	res := map[string]map[string]*ExtResult{}
+	for _, v := range exts {
+		e, ok := res[v.Name()]
+		if !ok {
+			e = map[string]*ExtResult{}
+			res[v.Name()] = e
+		}
+		e[v.Version()] = r.result(v)
+	}
+	return res
+}
+
+func (r *run) add(ext Extension, data map[string]interface{}, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := ext.Name() + "\n" + ext.Version()
+	res, ok := r.results[key]
+	if !ok {
+		res = NewResult(ext, map[string]interface{}{}, nil)
+		r.results[key] = res
+	}
+	for k, v := range data {
+		res.Data[k] = v
+	}
+	if err != nil {
+		res.Err = err
+	}
+}
+
+func (r *run) result(ext Extension) *ExtResult {
+	if _, ok := ext.(PerRun); !ok {
+		return ext.Result()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res, ok := r.results[ext.Name()+"\n"+ext.Version()]
+	if !ok {
+		return NewResult(ext, map[string]interface{}{}, nil)
+	}
+	return copyResult(res)
+}
+
+// PerRun is implemented by the extensions, which collect their results per run (see RunResult),
+// hence they are safe to share between the concurrent runs.
+type PerRun interface {
+	Extension
+	PerRun()
+}
+
+// RunResult keeps results of the extension, which is shared between runs: data & errors are collected
+// in the run of the context (see WithRun & RunResults), while Result reports only the latest run.
+// It is safe for concurrent use.
+type RunResult struct {
+	mu   sync.Mutex
+	run  uint64
+	data map[string]interface{}
+	err  error
+}
+
+// Add merges given data & error into the results of the run of the given context,
+// results of the previous runs are dropped.
+func (r *RunResult) Add(ctx context.Context, ext Extension, data map[string]interface{}, err error) {
+	if cur, ok := ctx.Value(runKey{}).(*run); ok {
+		cur.add(ext, data, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if id := RunID(ctx); r.data == nil || id != r.run {
+		r.run, r.data, r.err = id, map[string]interface{}{}, nil
+	}
+	for k, v := range data {
+		r.data[k] = v
+	}
+	if err != nil {
+		r.err = err
+	}
+}
+
+// Result returns copy of the results of the latest run of the given extension.
+func (r *RunResult) Result(ext Extension) *ExtResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return copyResult(NewResult(ext, r.data, r.err))
+}
+
+func copyResult(res *ExtResult) *ExtResult {
+	data := make(map[string]interface{}, len(res.Data))
+	for k, v := range res.Data {
+		data[k] = v
+	}
+	return &ExtResult{Name: res.Name, Version: res.Version, Err: res.Err, Data: data}
+}
@@ -0,0 +1,52 @@
+package extension
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRunExt struct {
+	*BaseExtension
+	res RunResult
+}
+
+func (ext *testRunExt) Result() *ExtResult {
+	return ext.res.Result(ext)
+}
+
+func (ext *testRunExt) PerRun() {}
+
+func Test_RunResults(t *testing.T) {
+	ext := &testRunExt{BaseExtension: NewExtension("test", "v1")}
+	base := NewExtension("base", "v1")
+	base.ExtResult = &ExtResult{Name: "base", Version: "v1", Data: map[string]interface{}{"foo": 1}}
+	exts := []Extension{ext, base}
+
+	ctx1, ctx2 := WithRun(context.TODO()), WithRun(context.TODO())
+	assert.Same(t, ctx1, WithRun(ctx1))
+	assert.NotEqual(t, RunID(ctx1), RunID(ctx2))
+
+	ext.res.Add(ctx1, ext, map[string]interface{}{"run": 1}, nil)
+	ext.res.Add(ctx2, ext, map[string]interface{}{"run": 2}, errors.New("boom"))
+
+	res1 := RunResults(ctx1, exts)
+	assert.Equal(t, 1, res1["test"]["v1"].Data["run"])
+	assert.Nil(t, res1["test"]["v1"].Err)
+	res2 := RunResults(ctx2, exts)
+	assert.Equal(t, 2, res2["test"]["v1"].Data["run"])
+	assert.EqualError(t, res2["test"]["v1"].Err, "boom")
+
+	// results of the extensions, which aren't collected per run
+	assert.Equal(t, 1, res1["base"]["v1"].Data["foo"])
+
+	// nothing is collected in a new run
+	assert.Empty(t, RunResults(WithRun(context.TODO()), exts)["test"]["v1"].Data)
+
+	// extension reports only the latest run
+	assert.Equal(t, 2, ext.Result().Data["run"])
+	ext.Result().Data["run"] = 3
+	assert.Equal(t, 2, ext.Result().Data["run"])
+}
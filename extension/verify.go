@@ -0,0 +1,155 @@
+package extension
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const trustedKeysFile = "trusted_keys"
+
+var (
+	// ErrChecksumRequired is returned when App is installed without expected checksum, while it is required.
+	ErrChecksumRequired = errors.New("checksum is required")
+	// ErrNoTrustedKeys is returned when App signature is provided, but there are no trusted keys to verify it.
+	ErrNoTrustedKeys = errors.New("no trusted keys to verify signature")
+	// ErrSignatureRequired is returned when there are trusted keys, but App signature is not provided.
+	ErrSignatureRequired = errors.New("signature is required")
+)
+
+// ChecksumMismatchError is returned when checksum of the App doesn't match the expected one.
+type ChecksumMismatchError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("checksum mismatch: expected %s, got %s", e.Expected, e.Actual)
+}
+
+// SignatureError is returned when signature of the App can't be verified with any of the trusted keys.
+type SignatureError struct {
+	Keys int
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("signature doesn't match any of %d trusted keys", e.Keys)
+}
+
+// Checksum returns SHA-256 checksum (hex) of the given data.
+func Checksum(bs []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(bs))
+}
+
+// ParsePublicKey parses base64 encoded ed25519 public key.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	bs, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("wrong public key format: %w", err)
+	}
+	if len(bs) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("wrong public key size: %d", len(bs))
+	}
+	return ed25519.PublicKey(bs), nil
+}
+
+// ParseSignature parses base64 encoded ed25519 signature.
+func ParseSignature(s string) ([]byte, error) {
+	bs, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("wrong signature format: %w", err)
+	}
+	if len(bs) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("wrong signature size: %d", len(bs))
+	}
+	return bs, nil
+}
+
+// DefaultTrustedKeysPath returns location of the trusted keys, which is "$HOME/.tagify/trusted_keys".
+func DefaultTrustedKeysPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, manifestDir, trustedKeysFile), nil
+}
+
+// LoadTrustedKeys reads base64 encoded ed25519 public keys from the given file,
+// one key per line, empty lines and lines starting with "#" are skipped.
+// No keys are returned if there is no file.
+func LoadTrustedKeys(path string) ([]ed25519.PublicKey, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trusted keys %q: %w", path, err)
+	}
+	defer f.Close()
+	keys := []ed25519.PublicKey{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, err := ParsePublicKey(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read trusted keys %q: %w", path, err)
+		}
+		keys = append(keys, k)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read trusted keys %q: %w", path, err)
+	}
+	return keys, nil
+}
+
+// verify checks given contents of the App against its expected checksum & signature.
+func (a *App) verify(bs []byte) error {
+	actual := Checksum(bs)
+	if a.expectedChecksum != "" {
+		if !strings.EqualFold(a.expectedChecksum, actual) {
+			return &ChecksumMismatchError{Expected: a.expectedChecksum, Actual: actual}
+		}
+	} else if a.requireChecksum {
+		return ErrChecksumRequired
+	}
+
+	if len(a.signature) == 0 {
+		if len(a.trustedKeys) > 0 {
+			return ErrSignatureRequired
+		}
+		return nil
+	}
+	if len(a.trustedKeys) == 0 {
+		return ErrNoTrustedKeys
+	}
+	for _, k := range a.trustedKeys {
+		if ed25519.Verify(k, bs, a.signature) {
+			return nil
+		}
+	}
+	return &SignatureError{Keys: len(a.trustedKeys)}
+}
+
+// Verify checks that executable of the installed App has not been changed since it was installed.
+func (a *App) Verify() error {
+	if a.checksum == "" {
+		return nil
+	}
+	bs, err := os.ReadFile(a.file)
+	if err != nil {
+		return fmt.Errorf("failed to read file %q: %w", a.file, err)
+	}
+	if actual := Checksum(bs); actual != a.checksum {
+		return &ChecksumMismatchError{Expected: a.checksum, Actual: actual}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package extension
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestApp(t *testing.T) (*App, string) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "app.sh")
+	assert.Nil(t, os.WriteFile(src, []byte(testAppScript), os.FileMode(0755)))
+	app := New("test-app", src)
+	app.SetInstallDir(filepath.Join(dir, "bin"))
+	return app, dir
+}
+
+func Test_Install_Checksum(t *testing.T) {
+	app, _ := newTestApp(t)
+	app.SetChecksum(Checksum([]byte(testAppScript)))
+	app.RequireChecksum(true)
+	assert.Nil(t, app.Install(context.TODO()))
+	assert.Nil(t, app.Verify())
+}
+
+func Test_Install_ChecksumMismatch(t *testing.T) {
+	app, dir := newTestApp(t)
+	app.SetChecksum(Checksum([]byte("something else")))
+	err := app.Install(context.TODO())
+	var mismatch *ChecksumMismatchError
+	assert.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, Checksum([]byte(testAppScript)), mismatch.Actual)
+	// nothing is written
+	assert.NoDirExists(t, filepath.Join(dir, "bin"))
+}
+
+func Test_Install_ChecksumRequired(t *testing.T) {
+	app, _ := newTestApp(t)
+	app.RequireChecksum(true)
+	assert.ErrorIs(t, app.Install(context.TODO()), ErrChecksumRequired)
+}
+
+func Test_Install_Signature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	assert.Nil(t, err)
+	otherPub, otherPriv, err := ed25519.GenerateKey(nil)
+	assert.Nil(t, err)
+	sig := ed25519.Sign(priv, []byte(testAppScript))
+
+	// valid signature
+	app, _ := newTestApp(t)
+	app.SetSignature(sig)
+	app.SetTrustedKeys([]ed25519.PublicKey{otherPub, pub})
+	assert.Nil(t, app.Install(context.TODO()))
+
+	// signature of the untrusted key
+	app, _ = newTestApp(t)
+	app.SetSignature(ed25519.Sign(otherPriv, []byte(testAppScript)))
+	app.SetTrustedKeys([]ed25519.PublicKey{pub})
+	var sigErr *SignatureError
+	assert.True(t, errors.As(app.Install(context.TODO()), &sigErr))
+
+	// no trusted keys
+	app, _ = newTestApp(t)
+	app.SetSignature(sig)
+	assert.ErrorIs(t, app.Install(context.TODO()), ErrNoTrustedKeys)
+
+	// no signature
+	app, _ = newTestApp(t)
+	app.SetTrustedKeys([]ed25519.PublicKey{pub})
+	assert.ErrorIs(t, app.Install(context.TODO()), ErrSignatureRequired)
+}
+
+func Test_Verify_Modified(t *testing.T) {
+	app, _ := newTestApp(t)
+	assert.Nil(t, app.Install(context.TODO()))
+	assert.Nil(t, os.WriteFile(app.File(), []byte("#!/bin/sh\necho hacked\n"), os.FileMode(0755)))
+	var mismatch *ChecksumMismatchError
+	assert.True(t, errors.As(FromManifest(app.ManifestEntry()).Verify(), &mismatch))
+}
+
+func Test_LoadTrustedKeys(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(nil)
+	assert.Nil(t, err)
+	path := filepath.Join(t.TempDir(), "trusted_keys")
+	content := "# team key\n\n" + base64.StdEncoding.EncodeToString(pub) + "\n"
+	assert.Nil(t, os.WriteFile(path, []byte(content), os.FileMode(0644)))
+
+	keys, err := LoadTrustedKeys(path)
+	assert.Nil(t, err)
+	assert.Len(t, keys, 1)
+	assert.True(t, pub.Equal(keys[0]))
+
+	keys, err = LoadTrustedKeys(filepath.Join(t.TempDir(), "missing"))
+	assert.Nil(t, err)
+	assert.Len(t, keys, 0)
+
+	_, err = ParseSignature("bm90IGEgc2lnbmF0dXJl")
+	assert.NotNil(t, err)
+}
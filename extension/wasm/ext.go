@@ -0,0 +1,253 @@
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/net/html"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/extension"
+	"github.com/zoomio/tagify/model"
+	"github.com/zoomio/tagify/processor"
+	thtml "github.com/zoomio/tagify/processor/html"
+)
+
+// ParseTagRequest is sent to the "tagify_parse_tag" for every parsed HTML tag.
+type ParseTagRequest struct {
+	Tag   string            `json:"tag"`
+	Attrs map[string]string `json:"attrs,omitempty"`
+	Line  int               `json:"line"`
+}
+
+// ParseTagResponse is returned by the "tagify_parse_tag".
+type ParseTagResponse struct {
+	// Text is appended to the contents of the current line.
+	Text string `json:"text,omitempty"`
+	// Weight overrides weight of the current line, if positive.
+	Weight float64 `json:"weight,omitempty"`
+	// Stop stops parsing of the HTML.
+	Stop  bool                   `json:"stop,omitempty"`
+	Data  map[string]interface{} `json:"data,omitempty"`
+	Error string                 `json:"error,omitempty"`
+}
+
+// TagifyRequest is sent to the "tagify_tagify" for every line of the HTML contents.
+type TagifyRequest struct {
+	Tag  string `json:"tag"`
+	Text string `json:"text"`
+}
+
+// TagifyResponse is returned by the "tagify_tagify".
+type TagifyResponse struct {
+	// Remove lists tags to be removed.
+	Remove []string `json:"remove,omitempty"`
+	// Scores are added to the scores of tags, missing tags are created.
+	Scores map[string]float64     `json:"scores,omitempty"`
+	Data   map[string]interface{} `json:"data,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// Ext makes WebAssembly module to act as an extension.
+// Module isn't safe for concurrent use, hence calls to it are serialized.
+// Data & errors are collected per run (see extension.RunResult), Result holds only the ones of the latest run.
+type Ext struct {
+	name    string
+	version string
+	mod     Module
+	limits  Limits
+
+	mu  sync.Mutex // serializes calls to the module
+	res extension.RunResult
+}
+
+// Load reads WebAssembly module from the given file and instantiates it with the default runtime.
+func Load(ctx context.Context, name, version, file string, limits Limits) (*Ext, error) {
+	rt := DefaultRuntime()
+	if rt == nil {
+		return nil, ErrNoRuntime
+	}
+	bin, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %q: %w", file, err)
+	}
+	return New(ctx, rt, name, version, bin, limits)
+}
+
+// New instantiates given WebAssembly module with the given runtime,
+// zero limits are replaced with the default ones.
+func New(ctx context.Context, rt Runtime, name, version string, bin []byte, limits Limits) (*Ext, error) {
+	if limits.MemoryPages == 0 {
+		limits.MemoryPages = DefaultMemoryPages
+	}
+	if limits.Timeout <= 0 {
+		limits.Timeout = DefaultTimeout
+	}
+	mod, err := rt.Instantiate(ctx, bin, limits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate %q: %w", name, err)
+	}
+	if !mod.HasFunc(FuncAlloc) {
+		_ = mod.Close(ctx)
+		return nil, fmt.Errorf("module %q doesn't export %q", name, FuncAlloc)
+	}
+	return &Ext{
+		name:    name,
+		version: version,
+		mod:     mod,
+		limits:  limits,
+	}, nil
+}
+
+func (ext *Ext) Name() string {
+	return ext.name
+}
+
+func (ext *Ext) Version() string {
+	return ext.version
+}
+
+func (ext *Ext) Result() *extension.ExtResult {
+	return ext.res.Result(ext)
+}
+
+// PerRun marks Ext as the one, which collects results per run.
+func (ext *Ext) PerRun() {}
+
+// Close releases WebAssembly module.
+func (ext *Ext) Close(ctx context.Context) error {
+	return ext.mod.Close(ctx)
+}
+
+func (ext *Ext) ParseTagContext(ctx context.Context, cfg *config.Config, token *html.Token, lineIdx int, cnts *thtml.HTMLContents) (bool, error) {
+	if !ext.mod.HasFunc(FuncParseTag) {
+		return false, nil
+	}
+	req := &ParseTagRequest{Tag: token.Data, Line: lineIdx}
+	if len(token.Attr) > 0 {
+		req.Attrs = make(map[string]string, len(token.Attr))
+		for _, a := range token.Attr {
+			req.Attrs[a.Key] = a.Val
+		}
+	}
+	resp := &ParseTagResponse{}
+	if err := ext.call(ctx, FuncParseTag, req, resp); err != nil {
+		return false, err
+	}
+	if err := ext.merge(ctx, resp.Data, resp.Error); err != nil {
+		return false, err
+	}
+	var appended bool
+	if resp.Text != "" {
+		cnts.Append(lineIdx, token.Data, []byte(resp.Text))
+		appended = true
+	}
+	if resp.Weight > 0 {
+		cnts.Weigh(lineIdx, resp.Weight)
+	}
+	if resp.Stop {
+		return appended, thtml.NewHTMLParseEndError()
+	}
+	return appended, nil
+}
+
+func (ext *Ext) TagifyContext(ctx context.Context, cfg *config.Config, line *thtml.HTMLLine, tokenIndex map[string]*model.Tag) error {
+	if !ext.mod.HasFunc(FuncTagify) {
+		return nil
+	}
+	resp := &TagifyResponse{}
+	if err := ext.call(ctx, FuncTagify, &TagifyRequest{Tag: line.Tag(), Text: line.Text()}, resp); err != nil {
+		return err
+	}
+	if err := ext.merge(ctx, resp.Data, resp.Error); err != nil {
+		return err
+	}
+	for _, v := range resp.Remove {
+		delete(tokenIndex, v)
+	}
+	for k, v := range resp.Scores {
+		item, ok := tokenIndex[k]
+		if !ok {
+			item = &model.Tag{Value: k}
+			tokenIndex[k] = item
+		}
+		item.Score += v
+	}
+	return nil
+}
+
+func (ext *Ext) PreRank(ctx context.Context, cfg *config.Config, tags []*model.Tag) ([]*model.Tag, error) {
+	return ext.rank(ctx, cfg, FuncPreRank, extension.HookPreRank, tags)
+}
+
+func (ext *Ext) PostRank(ctx context.Context, cfg *config.Config, tags []*model.Tag) ([]*model.Tag, error) {
+	return ext.rank(ctx, cfg, FuncPostRank, extension.HookPostRank, tags)
+}
+
+func (ext *Ext) rank(ctx context.Context, cfg *config.Config, fn, hook string, tags []*model.Tag) ([]*model.Tag, error) {
+	if !ext.mod.HasFunc(fn) {
+		return tags, nil
+	}
+	req := &extension.Request{
+		Protocol:    extension.ProtocolVersion,
+		Hook:        hook,
+		Source:      cfg.Source,
+		ContentType: cfg.ContentType.String(),
+		Lang:        cfg.Lang,
+		Tags:        processor.ToProtoTags(tags),
+	}
+	resp := &extension.Response{}
+	if err := ext.call(ctx, fn, req, resp); err != nil {
+		return nil, err
+	}
+	if err := ext.merge(ctx, resp.Data, resp.Error); err != nil {
+		return nil, err
+	}
+	if resp.Tags == nil {
+		return tags, nil
+	}
+	return processor.FromProtoTags(resp.Tags), nil
+}
+
+// call invokes function of the module with JSON encoded request & decodes its response,
+// every call is limited in time and is stopped once the given context is done.
+func (ext *Ext) call(ctx context.Context, fn string, req, resp interface{}) error {
+	in, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to encode request to %q: %w", fn, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ext.limits.Timeout)
+	defer cancel()
+
+	ext.mu.Lock()
+	out, err := ext.mod.Call(ctx, fn, in)
+	ext.mu.Unlock()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = &TimeoutError{Func: fn, Timeout: ext.limits.Timeout}
+		}
+		ext.res.Add(ctx, ext, nil, err)
+		return err
+	}
+	if err = json.Unmarshal(out, resp); err != nil {
+		err = fmt.Errorf("failed to decode response from %q: %w", fn, err)
+		ext.res.Add(ctx, ext, nil, err)
+		return err
+	}
+	return nil
+}
+
+// merge collects data returned by the module into the results of the run & turns its error message into an error.
+func (ext *Ext) merge(ctx context.Context, data map[string]interface{}, msg string) error {
+	var err error
+	if msg != "" {
+		err = errors.New(msg)
+	}
+	ext.res.Add(ctx, ext, data, err)
+	return err
+}
@@ -0,0 +1,197 @@
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/extension"
+	"github.com/zoomio/tagify/model"
+	"github.com/zoomio/tagify/processor"
+	thtml "github.com/zoomio/tagify/processor/html"
+)
+
+const testHTML = `
+<html>
+<body>
+<h2>What a beautiful day today!</h2>
+<h2>Stunning Sunset</h2>
+<div><img src="https://example.com/example.png" alt="ducks in the pond" /></div>
+</body>
+</html>
+`
+
+// testRuntime "instantiates" modules implemented with the Go functions.
+type testRuntime struct {
+	funcs map[string]func(ctx context.Context, in []byte) ([]byte, error)
+}
+
+func (r *testRuntime) Instantiate(ctx context.Context, bin []byte, limits Limits) (Module, error) {
+	return &testModule{funcs: r.funcs}, nil
+}
+
+type testModule struct {
+	funcs map[string]func(ctx context.Context, in []byte) ([]byte, error)
+}
+
+func (m *testModule) HasFunc(name string) bool {
+	_, ok := m.funcs[name]
+	return ok
+}
+
+func (m *testModule) Call(ctx context.Context, name string, in []byte) ([]byte, error) {
+	return m.funcs[name](ctx, in)
+}
+
+func (m *testModule) Close(ctx context.Context) error {
+	return nil
+}
+
+func alloc(ctx context.Context, in []byte) ([]byte, error) {
+	return nil, nil
+}
+
+func newTestExt(t *testing.T, funcs map[string]func(ctx context.Context, in []byte) ([]byte, error), limits Limits) *Ext {
+	funcs[FuncAlloc] = alloc
+	ext, err := New(context.TODO(), &testRuntime{funcs: funcs}, "test-wasm", "v0.0.1", nil, limits)
+	assert.Nil(t, err)
+	return ext
+}
+
+type inputReadCloser struct {
+	io.Reader
+}
+
+func (in *inputReadCloser) Close() error {
+	return nil
+}
+
+func Test_Ext_HTML(t *testing.T) {
+	ext := newTestExt(t, map[string]func(ctx context.Context, in []byte) ([]byte, error){
+		// appends alt text of images
+		FuncParseTag: func(ctx context.Context, in []byte) ([]byte, error) {
+			req := &ParseTagRequest{}
+			_ = json.Unmarshal(in, req)
+			resp := &ParseTagResponse{}
+			if req.Tag == "img" {
+				resp.Text = req.Attrs["alt"]
+				resp.Data = map[string]interface{}{"images": 1}
+			}
+			return json.Marshal(resp)
+		},
+		// removes "day" & boosts "sunset"
+		FuncTagify: func(ctx context.Context, in []byte) ([]byte, error) {
+			return json.Marshal(&TagifyResponse{Remove: []string{"day"}, Scores: map[string]float64{"sunset": 10}})
+		},
+	}, Limits{})
+
+	cfg := config.New(
+		config.TagWeightsString("h2:1|img:1"),
+		config.ContentOnly(false),
+		config.Extensions([]extension.Extension{ext}),
+	)
+	out := thtml.ProcessHTML(context.Background(), cfg, &inputReadCloser{strings.NewReader(testHTML)})
+
+	assert.Contains(t, out.RawTags, "ducks")
+	assert.Contains(t, out.RawTags, "pond")
+	assert.NotContains(t, out.RawTags, "day")
+	assert.Greater(t, out.RawTags["sunset"].Score, 10.0)
+
+	res := ext.Result()
+	assert.Nil(t, res.Err)
+	assert.Equal(t, 1.0, res.Data["images"])
+}
+
+func Test_Ext_PostRank(t *testing.T) {
+	ext := newTestExt(t, map[string]func(ctx context.Context, in []byte) ([]byte, error){
+		FuncPostRank: func(ctx context.Context, in []byte) ([]byte, error) {
+			req := &extension.Request{}
+			_ = json.Unmarshal(in, req)
+			return json.Marshal(&extension.Response{Tags: req.Tags[1:]})
+		},
+	}, Limits{})
+
+	cfg := config.New(config.Limit(5), config.Extensions([]extension.Extension{ext}))
+	processed := processor.Run(context.TODO(), cfg, []*model.Tag{
+		{Value: "cat", Score: 5},
+		{Value: "dog", Score: 3},
+		{Value: "bar", Score: 1},
+	})
+	assert.Equal(t, []string{"dog", "bar"}, model.ToStrings(processed))
+}
+
+func Test_Ext_Error(t *testing.T) {
+	ext := newTestExt(t, map[string]func(ctx context.Context, in []byte) ([]byte, error){
+		FuncPostRank: func(ctx context.Context, in []byte) ([]byte, error) {
+			return json.Marshal(&extension.Response{Error: "boom"})
+		},
+	}, Limits{})
+
+	cfg := config.New(config.Limit(5), config.Extensions([]extension.Extension{ext}))
+	processed := processor.Run(context.TODO(), cfg, []*model.Tag{{Value: "cat", Score: 5}})
+	assert.Equal(t, []string{"cat"}, model.ToStrings(processed))
+	assert.EqualError(t, ext.Result().Err, "boom")
+}
+
+func Test_Ext_Timeout(t *testing.T) {
+	ext := newTestExt(t, map[string]func(ctx context.Context, in []byte) ([]byte, error){
+		FuncPostRank: func(ctx context.Context, in []byte) ([]byte, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}, Limits{Timeout: 50 * time.Millisecond})
+
+	_, err := ext.PostRank(context.TODO(), config.New(), []*model.Tag{{Value: "cat", Score: 5}})
+	var timeoutErr *TimeoutError
+	assert.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, FuncPostRank, timeoutErr.Func)
+}
+
+func Test_New_NoAlloc(t *testing.T) {
+	_, err := New(context.TODO(), &testRuntime{funcs: nil}, "test-wasm", "v0.0.1", nil, Limits{})
+	assert.ErrorContains(t, err, FuncAlloc)
+}
+
+func Test_Ext_Runs(t *testing.T) {
+	ext := newTestExt(t, map[string]func(ctx context.Context, in []byte) ([]byte, error){
+		FuncPostRank: func(ctx context.Context, in []byte) ([]byte, error) {
+			req := &extension.Request{}
+			_ = json.Unmarshal(in, req)
+			return json.Marshal(&extension.Response{Data: map[string]interface{}{req.Source: len(req.Tags)}})
+		},
+	}, Limits{})
+
+	cfg := config.New(config.Limit(5), config.Extensions([]extension.Extension{ext}))
+	var wg sync.WaitGroup
+	for _, source := range []string{"a", "b", "c", "d"} {
+		wg.Add(1)
+		go func(source string) {
+			defer wg.Done()
+			c := cfg.Clone()
+			c.Source = source
+			ctx := extension.WithRun(context.TODO())
+			processor.Run(ctx, c, []*model.Tag{{Value: "cat", Score: 5}})
+			_ = ext.Result()
+
+			// data of the other runs isn't mixed in
+			res := extension.RunResults(ctx, c.Extensions)["test-wasm"]["v0.0.1"]
+			assert.Equal(t, map[string]interface{}{source: 1.0}, res.Data)
+		}(source)
+	}
+	wg.Wait()
+
+	// results of the previous runs are reset & copy is returned
+	processor.Run(context.TODO(), cfg, []*model.Tag{{Value: "cat", Score: 5}})
+	res := ext.Result()
+	assert.Equal(t, map[string]interface{}{"": 1.0}, res.Data)
+	res.Data["foo"] = 1
+	assert.NotContains(t, ext.Result().Data, "foo")
+}
@@ -0,0 +1,36 @@
+;; Fixture of the wazero runtime tests, echo.wasm is its binary.
+;;
+;;   - "tagify_pre_rank" returns its request, i.e. tags are kept as is;
+;;   - "tagify_post_rank" never returns;
+;;   - "tagify_tagify" grows memory by 1024 pages (64 MiB) before returning its request.
+(module
+  (memory (export "memory") 1)
+
+  (func (export "tagify_alloc") (param i32) (result i32)
+    i32.const 1024)
+
+  (func $echo (param i32 i32) (result i64)
+    local.get 0
+    i64.extend_i32_u
+    i64.const 32
+    i64.shl
+    local.get 1
+    i64.extend_i32_u
+    i64.or)
+
+  (func (export "tagify_pre_rank") (param i32 i32) (result i64)
+    local.get 0
+    local.get 1
+    call $echo)
+
+  (func (export "tagify_post_rank") (param i32 i32) (result i64)
+    (loop $forever
+      br $forever)
+    unreachable)
+
+  (func (export "tagify_tagify") (param i32 i32) (result i64)
+    (if (i32.lt_s (memory.grow (i32.const 1024)) (i32.const 0))
+      (then unreachable))
+    local.get 0
+    local.get 1
+    call $echo))
@@ -0,0 +1,96 @@
+// Package wasm allows to run extensions compiled to WebAssembly inside of the Tagify process.
+//
+// WebAssembly module is sandboxed: it has no access to the file system nor network,
+// its linear memory and time of every call are limited (see Limits).
+//
+// Module communicates with the Tagify through its linear memory, it must export:
+//
+//   - "memory" - linear memory of the module;
+//   - "tagify_alloc(size i32) i32" - allocates size bytes and returns pointer to them.
+//
+// and any of the hooks:
+//
+//   - "tagify_parse_tag(ptr i32, len i32) i64" - receives ParseTagRequest, returns ParseTagResponse;
+//   - "tagify_tagify(ptr i32, len i32) i64" - receives TagifyRequest, returns TagifyResponse;
+//   - "tagify_pre_rank(ptr i32, len i32) i64" - receives extension.Request, returns extension.Response;
+//   - "tagify_post_rank(ptr i32, len i32) i64" - receives extension.Request, returns extension.Response.
+//
+// Hook receives JSON encoded request, which Tagify writes into the memory allocated via "tagify_alloc",
+// and returns JSON encoded response, where pointer to the response is in the upper 32 bits of the result
+// and its length is in the lower 32 bits.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Exported functions of the module.
+const (
+	FuncAlloc    = "tagify_alloc"
+	FuncParseTag = "tagify_parse_tag"
+	FuncTagify   = "tagify_tagify"
+	FuncPreRank  = "tagify_pre_rank"
+	FuncPostRank = "tagify_post_rank"
+)
+
+// Default limits.
+const (
+	DefaultMemoryPages = 256 // 16 MiB
+	DefaultTimeout     = time.Second
+)
+
+// ErrNoRuntime is returned when Tagify is built without WebAssembly runtime.
+var ErrNoRuntime = errors.New("WebAssembly runtime is not available, build Tagify with \"-tags tagify_wasm\"")
+
+// TimeoutError is returned when hook doesn't complete within the time limit.
+type TimeoutError struct {
+	Func    string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %v", e.Func, e.Timeout)
+}
+
+// Limits restrict resources available to the module.
+type Limits struct {
+	// MemoryPages is the maximum size of the linear memory in 64 KiB pages.
+	MemoryPages uint32
+	// Timeout is the maximum duration of a single hook call.
+	Timeout time.Duration
+}
+
+// DefaultLimits returns limits used, when none are provided.
+func DefaultLimits() Limits {
+	return Limits{MemoryPages: DefaultMemoryPages, Timeout: DefaultTimeout}
+}
+
+// Runtime compiles & instantiates WebAssembly modules.
+type Runtime interface {
+	// Instantiate instantiates given binary of the module with the given limits.
+	Instantiate(ctx context.Context, bin []byte, limits Limits) (Module, error)
+}
+
+// Module is an instance of the WebAssembly module.
+type Module interface {
+	// HasFunc tells whether the module exports function with the given name.
+	HasFunc(name string) bool
+	// Call writes input into the memory of the module, calls the function and returns its output.
+	Call(ctx context.Context, name string, in []byte) ([]byte, error)
+	// Close releases all resources of the module.
+	Close(ctx context.Context) error
+}
+
+// newRuntime is set by the build of Tagify with WebAssembly runtime.
+var newRuntime func() Runtime
+
+// DefaultRuntime returns WebAssembly runtime Tagify is built with, nil if there is none.
+func DefaultRuntime() Runtime {
+	if newRuntime == nil {
+		return nil
+	}
+	return newRuntime()
+}
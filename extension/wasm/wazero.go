@@ -0,0 +1,110 @@
+//go:build tagify_wasm
+
+package wasm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+func init() {
+	newRuntime = func() Runtime {
+		return &wazeroRuntime{}
+	}
+}
+
+// wazeroRuntime runs WebAssembly modules with the pure Go runtime github.com/tetratelabs/wazero.
+type wazeroRuntime struct{}
+
+func (r *wazeroRuntime) Instantiate(ctx context.Context, bin []byte, limits Limits) (Module, error) {
+	cfg := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(limits.MemoryPages).
+		WithCloseOnContextDone(true)
+	rt := wazero.NewRuntimeWithConfig(ctx, cfg)
+
+	// WASI without file system & network, required by modules built with TinyGo, Rust & etc.
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, rt); err != nil {
+		_ = rt.Close(ctx)
+		return nil, err
+	}
+
+	compiled, err := rt.CompileModule(ctx, bin)
+	if err != nil {
+		_ = rt.Close(ctx)
+		return nil, err
+	}
+	m := &wazeroModule{rt: rt, compiled: compiled}
+	if err = m.instantiate(ctx); err != nil {
+		_ = rt.Close(ctx)
+		return nil, err
+	}
+	return m, nil
+}
+
+// wazeroModule is closed by the runtime once its call runs out of time,
+// in which case it is instantiated again by the next call, hence state of the module is reset.
+type wazeroModule struct {
+	rt       wazero.Runtime
+	compiled wazero.CompiledModule
+	mod      api.Module
+}
+
+func (m *wazeroModule) instantiate(ctx context.Context) error {
+	mod, err := m.rt.InstantiateModule(ctx, m.compiled, wazero.NewModuleConfig().WithName(""))
+	if err != nil {
+		return err
+	}
+	if mod.Memory() == nil {
+		_ = mod.Close(ctx)
+		return fmt.Errorf("module doesn't export memory")
+	}
+	m.mod = mod
+	return nil
+}
+
+func (m *wazeroModule) HasFunc(name string) bool {
+	_, ok := m.compiled.ExportedFunctions()[name]
+	return ok
+}
+
+func (m *wazeroModule) Call(ctx context.Context, name string, in []byte) ([]byte, error) {
+	if m.mod.IsClosed() {
+		if err := m.instantiate(ctx); err != nil {
+			return nil, fmt.Errorf("failed to instantiate closed module: %w", err)
+		}
+	}
+
+	fn := m.mod.ExportedFunction(name)
+	if fn == nil {
+		return nil, fmt.Errorf("function %q is not exported", name)
+	}
+
+	res, err := m.mod.ExportedFunction(FuncAlloc).Call(ctx, uint64(len(in)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate %d bytes: %w", len(in), err)
+	}
+	ptr := uint32(res[0])
+	if !m.mod.Memory().Write(ptr, in) {
+		return nil, fmt.Errorf("failed to write %d bytes at %d: out of memory range", len(in), ptr)
+	}
+
+	res, err = fn.Call(ctx, uint64(ptr), uint64(len(in)))
+	if err != nil {
+		return nil, err
+	}
+	outPtr, outLen := uint32(res[0]>>32), uint32(res[0])
+	out, ok := m.mod.Memory().Read(outPtr, outLen)
+	if !ok {
+		return nil, fmt.Errorf("failed to read %d bytes at %d: out of memory range", outLen, outPtr)
+	}
+	// memory of the module might be reused by the next call
+	return append([]byte(nil), out...), nil
+}
+
+func (m *wazeroModule) Close(ctx context.Context) error {
+	return m.rt.Close(ctx)
+}
@@ -0,0 +1,69 @@
+//go:build tagify_wasm
+
+package wasm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/model"
+)
+
+// see testdata/echo.wat
+const echoWasm = "testdata/echo.wasm"
+
+func Test_Wazero_MemoryLimit(t *testing.T) {
+	bin, err := os.ReadFile(echoWasm)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name    string
+		pages   uint32
+		wantErr bool
+	}{
+		{"over the limit", DefaultMemoryPages, true},
+		{"within the limit", 2048, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mod, err := DefaultRuntime().Instantiate(context.TODO(), bin, Limits{MemoryPages: tt.pages, Timeout: DefaultTimeout})
+			assert.Nil(t, err)
+			defer mod.Close(context.TODO())
+
+			out, err := mod.Call(context.TODO(), FuncTagify, []byte(`{"tag":"p"}`))
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, `{"tag":"p"}`, string(out))
+		})
+	}
+}
+
+func Test_Wazero_Timeout(t *testing.T) {
+	ext, err := Load(context.TODO(), "echo", "v0.0.1", echoWasm, Limits{Timeout: 100 * time.Millisecond})
+	assert.Nil(t, err)
+	defer ext.Close(context.TODO())
+
+	cfg := config.New()
+	tags := []*model.Tag{{Value: "cat", Score: 5, Count: 1}}
+
+	_, err = ext.PostRank(context.TODO(), cfg, tags)
+	var timeoutErr *TimeoutError
+	assert.True(t, errors.As(err, &timeoutErr))
+
+	// module closed by the timed out call is instantiated again
+	res, err := ext.PreRank(context.TODO(), cfg, tags)
+	assert.Nil(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, "cat", res[0].Value)
+	assert.Equal(t, 5.0, res[0].Score)
+}
@@ -1,13 +1,17 @@
 module github.com/zoomio/tagify
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/abadojack/whatlanggo v1.0.1
 	github.com/go-ego/gse v0.70.2
 	github.com/jinzhu/inflection v0.0.0-20180308033659-04140366298a
 	github.com/stretchr/testify v1.8.4
+	github.com/tetratelabs/wazero v1.7.3
 	github.com/zoomio/inout v0.14.0
 	github.com/zoomio/stopwords v0.11.0
 	golang.org/x/net v0.0.0-20220107192237-5cfca573fb4d
+	golang.org/x/text v0.22.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -26,7 +30,6 @@ require (
 	github.com/vcaesar/cedar v0.20.1 // indirect
 	golang.org/x/sys v0.6.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20200227125254-8fa46927fb4f // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 go 1.22
@@ -2,10 +2,16 @@ package tagify
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"path/filepath"
 	"strings"
 
 	"github.com/zoomio/inout"
+
+	"github.com/zoomio/tagify/cache"
+	"github.com/zoomio/tagify/model"
+	"github.com/zoomio/tagify/safeguard"
 )
 
 // in - Input. This struct provides methods for reading strings
@@ -14,6 +20,12 @@ type in struct {
 	source string
 	reader *inout.Reader
 	ContentType
+
+	// HTTP cache
+	cache       *cache.Cache
+	entry       *cache.Entry // fetched or cached contents along with the previously cached result
+	notModified bool
+	fingerprint string // of the options of the run, empty if results aren't cached
 }
 
 // newIn initializes an input stream from STDIN, file or web page.
@@ -23,12 +35,29 @@ type in struct {
 func newIn(ctx context.Context, cfg *Config) (in, error) {
 	in := in{source: cfg.Source}
 
-	if strings.HasPrefix(cfg.Source, "http://") || strings.HasPrefix(cfg.Source, "https://") || cfg.Query != "" {
+	if isHTTP(cfg.Source) || cfg.Query != "" {
 		in.ContentType = HTML
 	} else if strings.ToLower(filepath.Ext(cfg.Source)) == ".md" {
 		in.ContentType = Markdown
 	}
 
+	var guard *safeguard.Guard
+	if cfg.Source != "" && safeguard.Enabled(cfg) {
+		guard = safeguard.New(cfg)
+		if err := guard.Check(ctx, cfg.Source); err != nil {
+			return in, err
+		}
+	}
+
+	if isCacheable(cfg) {
+		return newCachedIn(ctx, cfg, in, guard)
+	}
+
+	// headless calls are made by the browser, hence only checked upfront
+	if guard != nil && isHTTP(cfg.Source) && !isHeadless(cfg) {
+		return newGuardedIn(ctx, cfg, in, guard)
+	}
+
 	r, err := inout.NewInOut(ctx,
 		inout.Source(cfg.Source),
 		inout.Query(cfg.Query),
@@ -48,6 +77,93 @@ func newIn(ctx context.Context, cfg *Config) (in, error) {
 	return in, err
 }
 
+// newCachedIn fetches web page via the HTTP cache,
+// in case if page hasn't been modified, input reads the cached contents or its cached result is used (see cachedResult).
+func newCachedIn(ctx context.Context, cfg *Config, in in, guard *safeguard.Guard) (in, error) {
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	var client *http.Client
+	if guard != nil {
+		client = guard.Client()
+	} else if cfg.Timeout > 0 {
+		client = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	in.cache = cache.New(cfg.CacheDir, client)
+	resp, err := in.cache.Fetch(ctx, cfg.Source, cfg.UserAgent)
+	if err != nil {
+		return in, fmt.Errorf("error in fetching provided source=%s: %w", cfg.Source, err)
+	}
+
+	in.fingerprint, _ = cfg.Fingerprint()
+	in.notModified = resp.NotModified()
+	if in.notModified {
+		if cfg.Verbose {
+			fmt.Printf("source %s has not been modified, using cached contents\n", cfg.Source)
+		}
+		in.entry = resp.Entry
+	} else {
+		// cached result stays valid as long as the contents are the same (see cachedResult)
+		e := cache.Entry{URL: cfg.Source}
+		if resp.Cached != nil {
+			e = *resp.Cached
+		}
+		e.ETag, e.LastModified, e.Body = resp.ETag, resp.LastModified, resp.Body
+		in.entry = &e
+		if err = in.cache.Put(in.entry); err != nil && cfg.Verbose {
+			fmt.Printf("failed to cache contents: %v\n", err)
+		}
+	}
+
+	in.reader = inout.NewFromString(string(resp.Body))
+
+	return in, nil
+}
+
+// cachedResult returns result cached for the options of the run,
+// given document hash is empty until the contents are processed, then result is only returned if it matches.
+// Otherwise result is only returned if the source hasn't been modified.
+func (in *in) cachedResult(docHash string) *model.Result {
+	if in.entry == nil {
+		return nil
+	}
+	if (docHash == "" && !in.notModified) || (docHash != "" && docHash != in.entry.DocHash) {
+		return nil
+	}
+	return in.entry.Result(in.fingerprint)
+}
+
+// storeResult saves given result along with the contents in the HTTP cache (if it is enabled for the input).
+func (in *in) storeResult(res *model.Result) error {
+	if in.cache == nil || in.entry == nil || in.fingerprint == "" || res.Err != nil || res.Meta == nil {
+		return nil
+	}
+	in.entry.SetResult(in.fingerprint, res)
+	return in.cache.Put(in.entry)
+}
+
+// newGuardedIn fetches web page via the HTTP client, which enforces the safeguards.
+func newGuardedIn(ctx context.Context, cfg *Config, in in, guard *safeguard.Guard) (in, error) {
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	bs, err := guard.Get(ctx, cfg.Source, cfg.UserAgent)
+	if err != nil {
+		return in, fmt.Errorf("error in fetching provided source=%s: %w", cfg.Source, err)
+	}
+
+	in.reader = inout.NewFromString(string(bs))
+
+	return in, nil
+}
+
 // newInFromString ...
 func newInFromString(input string, contentType ContentType) in {
 	r := inout.NewFromString(input)
@@ -78,3 +194,18 @@ func (in *in) ReadLines() ([]string, error) {
 	}
 	return lines, nil
 }
+
+func isHTTP(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+func isHeadless(cfg *Config) bool {
+	return cfg.Query != "" || cfg.WaitFor != "" || cfg.WaitUntil > 0 || cfg.Screenshot
+}
+
+// isCacheable tells whether contents of the source could be stored in the HTTP cache,
+// pages of the headless & full site modes are never cached, results are cached only if options have
+// the fingerprint (see config.Config.Fingerprint).
+func isCacheable(cfg *Config) bool {
+	return cfg.CacheDir != "" && isHTTP(cfg.Source) && !isHeadless(cfg) && !cfg.FullSite
+}
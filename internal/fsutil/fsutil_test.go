@@ -0,0 +1,28 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "index.json")
+
+	assert.Nil(t, WriteFile(path, []byte("foo")))
+	assert.Nil(t, WriteFile(path, []byte("bar")))
+
+	bs, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "bar", string(bs))
+
+	// temporary files are gone
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+
+	assert.NotNil(t, WriteFile(filepath.Join(dir, "missing", "index.json"), []byte("foo")))
+}
@@ -0,0 +1,13 @@
+// Package testutil provides helpers shared by the tests of Tagify.
+package testutil
+
+import "github.com/zoomio/tagify/model"
+
+// NewResult creates result with the raw tags of the given scores.
+func NewResult(scores map[string]float64) *model.Result {
+	raw := make(map[string]*model.Tag, len(scores))
+	for v, s := range scores {
+		raw[v] = &model.Tag{Value: v, Score: s}
+	}
+	return &model.Result{Meta: &model.Meta{}, RawTags: raw}
+}
@@ -8,11 +8,15 @@ import (
 type Config = config.Config
 type Option = config.Option
 type ContentType = config.ContentType
+type Algorithm = config.Algorithm
+type Decay = config.Decay
+type TokenRule = config.TokenRule
 
 var (
-	Source   = config.Source
-	Language = config.Language
-	Content  = config.Content
+	Source       = config.Source
+	Language     = config.Language
+	SectionLangs = config.SectionLangs
+	Content      = config.Content
 
 	Timeout = config.Timeout
 
@@ -23,14 +27,49 @@ var (
 	Screenshot = config.Screenshot
 	UserAgent  = config.UserAgent
 
+	// caching
+	Cache = config.Cache
+
+	// safeguards
+	BlockPrivateNetworks = config.BlockPrivateNetworks
+	AllowedSchemes       = config.AllowedSchemes
+	AllowedPorts         = config.AllowedPorts
+	MaxResponseSize      = config.MaxResponseSize
+	MaxRedirects         = config.MaxRedirects
+
 	// misc
-	TargetType  = config.TargetType
-	Limit       = config.Limit
-	Verbose     = config.Verbose
-	NoStopWords = config.NoStopWords
-	StopWords   = config.StopWords
-	ContentOnly = config.ContentOnly
-	FullSite    = config.FullSite
+	TargetType       = config.TargetType
+	TargetTypeString = config.TargetTypeString
+	Limit            = config.Limit
+	Verbose          = config.Verbose
+	NoStopWords      = config.NoStopWords
+	StopWords        = config.StopWords
+	ContentOnly      = config.ContentOnly
+	FullSite         = config.FullSite
+	Stream           = config.Stream
+	MaxInputSize     = config.MaxInputSize
+	Signatures       = config.Signatures
+
+	// token preservation
+	PreserveTokens   = config.PreserveTokens
+	PreserveString   = config.PreserveString
+	PreservePatterns = config.PreservePatterns
+	Hashtags         = config.Hashtags
+	Mentions         = config.Mentions
+	Versions         = config.Versions
+	Identifiers      = config.Identifiers
+	ProgLangs        = config.ProgLangs
+
+	// keywords
+	KeywordAlgorithm = config.KeywordAlgorithm
+	AlgorithmString  = config.AlgorithmString
+	Frequency        = config.Frequency
+	RAKE             = config.RAKE
+	YAKE             = config.YAKE
+	Entities         = config.Entities
+
+	// diversification
+	Diversify = config.Diversify
 
 	// weighing
 	TagWeightsString      = config.TagWeightsString
@@ -40,6 +79,11 @@ var (
 	ExcludeTagsString     = config.ExcludeTagsString
 	AllTagWeights         = config.AllTagWeights
 	AdjustScores          = config.AdjustScores
+	PositionWeights       = config.PositionWeights
+	PositionString        = config.PositionString
+	StepDecay             = config.StepDecay
+	LinearDecay           = config.LinearDecay
+	ExpDecay              = config.ExpDecay
 
 	// content types
 	Unknown       = config.Unknown
@@ -49,4 +93,10 @@ var (
 	ContentTypeOf = config.ContentTypeOf
 
 	Extensions = config.Extensions
+	UserDict   = config.UserDict
+	Decompound = config.Decompound
+
+	// normalization
+	SkipNormalize = config.SkipNormalize
+	FoldAccents   = config.FoldAccents
 )
@@ -0,0 +1,54 @@
+package model
+
+// Kinds of the entities (see config.Entities)
+const (
+	// NoEntity is a regular tag.
+	NoEntity EntityKind = iota
+	// Name is a capitalized sequence of words, e.g. "Apple" or "New York Times".
+	Name
+	// Acronym is a word of capital letters, e.g. "NASA".
+	Acronym
+	// Title is a quoted title, e.g. "War and Peace".
+	Title
+)
+
+var (
+	EntityKinds = [...]string{
+		"",
+		"name",
+		"acronym",
+		"title",
+	}
+)
+
+// EntityKind tells whether tag is an entity and what kind of.
+type EntityKind byte
+
+// EntityKindOf returns EntityKind based on string value and tells whether it is known.
+func EntityKindOf(kind string) (EntityKind, bool) {
+	for i, key := range EntityKinds {
+		if key == kind {
+			return EntityKind(i), true
+		}
+	}
+	return NoEntity, false
+}
+
+// String ...
+func (k EntityKind) String() string {
+	if k > Title {
+		return "unknown"
+	}
+	return EntityKinds[k]
+}
+
+// MarshalText encodes kind by its name.
+func (k EntityKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes kind from its name, unknown names are decoded as NoEntity.
+func (k *EntityKind) UnmarshalText(text []byte) error {
+	*k, _ = EntityKindOf(string(text))
+	return nil
+}
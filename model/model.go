@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"fmt"
 	"io"
 
@@ -26,6 +27,11 @@ type Tag struct {
 	Docs int
 	// DocsCount is the number of documents in a text
 	DocsCount int
+	// Sentences are indexes of the documents in which the tag appeared,
+	// only collected for the diversification (see config.Config.Diversify)
+	Sentences []int `json:"-"`
+	// Entity tells whether tag is a named entity with the original casing (see config.Entities)
+	Entity EntityKind `json:",omitempty"`
 }
 
 // Meta extra information.
@@ -34,7 +40,11 @@ type Meta struct {
 	DocTitle    string
 	DocHash     string
 	Lang        string
-	Screenshot  []byte // bytes of the viewport screenshot in png
+	Langs       map[string]float64 // shares of the languages, if detected per section (see config.SectionLangs)
+	Screenshot  []byte             // bytes of the viewport screenshot in png
+	// signatures for the near-duplicate detection (see config.Signatures & dedup package)
+	SimHash uint64
+	MinHash []uint64
 }
 
 func (t *Tag) String() string {
@@ -108,7 +118,8 @@ func (r *Result) TagsStrings() []string {
 
 // ProcessFunc represents an arbitrary handler,
 // which goes through given reader and produces tags.
-type ProcessFunc func(c *config.Config, reader io.ReadCloser) *Result
+// It returns Result with the error of the context once the context is done.
+type ProcessFunc func(ctx context.Context, c *config.Config, reader io.ReadCloser) *Result
 
 func flatten(dict map[string]*Tag) []*Tag {
 	flat := make([]*Tag, len(dict))
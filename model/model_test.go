@@ -1,6 +1,8 @@
 package model
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -73,3 +75,15 @@ func Test_ToStrings(t *testing.T) {
 	assert.Equal(t, "foo", strs[0])
 	assert.Equal(t, "bar", strs[1])
 }
+
+func Test_EntityKind_JSON(t *testing.T) {
+	bs, err := json.Marshal([]*Tag{{Value: "NASA", Entity: Acronym}, {Value: "space"}})
+	assert.Nil(t, err)
+	assert.Contains(t, string(bs), `"Entity":"acronym"`)
+	assert.Equal(t, 1, strings.Count(string(bs), "Entity"))
+
+	var tags []*Tag
+	assert.Nil(t, json.Unmarshal(bs, &tags))
+	assert.Equal(t, Acronym, tags[0].Entity)
+	assert.Equal(t, NoEntity, tags[1].Entity)
+}
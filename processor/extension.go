@@ -0,0 +1,154 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/extension"
+	"github.com/zoomio/tagify/model"
+)
+
+// ExtPreRank executed with all tags found in the contents, before ranking.
+type ExtPreRank interface {
+	extension.Extension
+
+	// PreRank returns tags to be ranked.
+	PreRank(ctx context.Context, cfg *config.Config, tags []*model.Tag) ([]*model.Tag, error)
+}
+
+// ExtPostRank executed with the ranked tags.
+type ExtPostRank interface {
+	extension.Extension
+
+	// PostRank returns final tags.
+	PostRank(ctx context.Context, cfg *config.Config, tags []*model.Tag) ([]*model.Tag, error)
+}
+
+func extPreRank(ctx context.Context, cfg *config.Config, tags []*model.Tag) []*model.Tag {
+	for _, v := range cfg.Extensions {
+		e, ok := v.(ExtPreRank)
+		if !ok {
+			continue
+		}
+		res, err := e.PreRank(ctx, cfg, tags)
+		if err != nil {
+			if cfg.Verbose {
+				fmt.Printf("error in pre-ranking %q %s: %v\n", v.Name(), v.Version(), err)
+			}
+			continue
+		}
+		tags = res
+	}
+	return tags
+}
+
+func extPostRank(ctx context.Context, cfg *config.Config, tags []*model.Tag) []*model.Tag {
+	for _, v := range cfg.Extensions {
+		e, ok := v.(ExtPostRank)
+		if !ok {
+			continue
+		}
+		res, err := e.PostRank(ctx, cfg, tags)
+		if err != nil {
+			if cfg.Verbose {
+				fmt.Printf("error in post-ranking %q %s: %v\n", v.Name(), v.Version(), err)
+			}
+			continue
+		}
+		tags = res
+	}
+	return tags
+}
+
+// AppExt makes an installed extension App to act as an extension,
+// App is executed on every hook (see extension.Request & extension.Response).
+// Data & error are collected per run (see extension.RunResult), Result holds only the ones of the latest run.
+type AppExt struct {
+	app *extension.App
+	res extension.RunResult
+}
+
+// NewAppExt creates new instance of AppExt for the given App.
+func NewAppExt(app *extension.App) *AppExt {
+	return &AppExt{app: app}
+}
+
+func (ext *AppExt) Name() string {
+	return ext.app.Name()
+}
+
+func (ext *AppExt) Version() string {
+	return ext.app.Version()
+}
+
+func (ext *AppExt) Result() *extension.ExtResult {
+	return ext.res.Result(ext)
+}
+
+// PerRun marks AppExt as the one, which collects results per run.
+func (ext *AppExt) PerRun() {}
+
+func (ext *AppExt) PreRank(ctx context.Context, cfg *config.Config, tags []*model.Tag) ([]*model.Tag, error) {
+	return ext.call(ctx, cfg, extension.HookPreRank, tags)
+}
+
+func (ext *AppExt) PostRank(ctx context.Context, cfg *config.Config, tags []*model.Tag) ([]*model.Tag, error) {
+	return ext.call(ctx, cfg, extension.HookPostRank, tags)
+}
+
+func (ext *AppExt) call(ctx context.Context, cfg *config.Config, hook string, tags []*model.Tag) ([]*model.Tag, error) {
+	req := &extension.Request{
+		Hook:        hook,
+		Source:      cfg.Source,
+		ContentType: cfg.ContentType.String(),
+		Lang:        cfg.Lang,
+		Tags:        ToProtoTags(tags),
+	}
+	resp, err := ext.app.Call(ctx, req)
+	if err != nil {
+		ext.res.Add(ctx, ext, nil, err)
+		return nil, err
+	}
+	ext.res.Add(ctx, ext, resp.Data, nil)
+	if resp.Tags == nil {
+		return tags, nil
+	}
+	return FromProtoTags(resp.Tags), nil
+}
+
+// ToProtoTags converts tags into the ones exchanged with extensions.
+func ToProtoTags(tags []*model.Tag) []*extension.ProtoTag {
+	res := make([]*extension.ProtoTag, len(tags))
+	for i, t := range tags {
+		res[i] = &extension.ProtoTag{
+			Value:     t.Value,
+			Score:     t.Score,
+			Count:     t.Count,
+			Docs:      t.Docs,
+			DocsCount: t.DocsCount,
+			Entity:    t.Entity.String(),
+		}
+	}
+	return res
+}
+
+// FromProtoTags converts tags received from extensions, tags without value are skipped.
+func FromProtoTags(tags []*extension.ProtoTag) []*model.Tag {
+	res := make([]*model.Tag, 0, len(tags))
+	for _, t := range tags {
+		if t == nil || t.Value == "" {
+			continue
+		}
+		entity, _ := model.EntityKindOf(t.Entity)
+		res = append(res, &model.Tag{
+			Value:     t.Value,
+			Score:     t.Score,
+			Count:     t.Count,
+			Docs:      t.Docs,
+			DocsCount: t.DocsCount,
+			Entity:    entity,
+		})
+	}
+	return res
+}
@@ -0,0 +1,153 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/extension"
+	"github.com/zoomio/tagify/model"
+)
+
+const helperEnv = "TAGIFY_TEST_EXT_APP"
+
+// TestMain allows test binary to act as an extension App,
+// when it is executed with the helperEnv environment variable.
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(helperEnv); mode != "" {
+		runTestExtApp(mode)
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func runTestExtApp(mode string) {
+	req := &extension.Request{}
+	if err := json.NewDecoder(os.Stdin).Decode(req); err != nil {
+		os.Exit(1)
+	}
+	resp := &extension.Response{Data: map[string]interface{}{req.Hook: len(req.Tags)}}
+	// mode might be given by the source of the run
+	if mode == "source" {
+		mode = req.Source
+	}
+	switch mode {
+	case "drop":
+		// drops "cat" before ranking & appends "zoo" after
+		if req.Hook == extension.HookPreRank {
+			for _, t := range req.Tags {
+				if t.Value != "cat" {
+					resp.Tags = append(resp.Tags, t)
+				}
+			}
+		} else {
+			resp.Tags = append(req.Tags, &extension.ProtoTag{Value: "zoo", Score: 1})
+		}
+	case "fail":
+		resp.Error = "boom"
+	case "sleep":
+		time.Sleep(time.Second)
+	}
+	_ = json.NewEncoder(os.Stdout).Encode(resp)
+}
+
+func newTestAppExt(t *testing.T, mode string) *AppExt {
+	t.Setenv(helperEnv, mode)
+	return NewAppExt(extension.Load("test-app", os.Args[0], "v0.0.1"))
+}
+
+func newTestItems() []*model.Tag {
+	return []*model.Tag{
+		{Value: "cat", Score: 5},
+		{Value: "dog", Score: 3},
+		{Value: "bar", Score: 1},
+	}
+}
+
+func Test_AppExt(t *testing.T) {
+	ext := newTestAppExt(t, "drop")
+	c := config.New(config.Limit(5), config.Extensions([]extension.Extension{ext}))
+	processed := Run(context.TODO(), c, newTestItems())
+	assert.Equal(t, []string{"dog", "bar", "zoo"}, model.ToStrings(processed))
+
+	res := ext.Result()
+	assert.Nil(t, res.Err)
+	assert.Equal(t, "test-app", res.Name)
+	assert.Equal(t, "v0.0.1", res.Version)
+	assert.Equal(t, 3.0, res.Data[extension.HookPreRank])
+	assert.Equal(t, 2.0, res.Data[extension.HookPostRank])
+}
+
+func Test_AppExt_Error(t *testing.T) {
+	ext := newTestAppExt(t, "fail")
+	c := config.New(config.Limit(5), config.Extensions([]extension.Extension{ext}))
+	processed := Run(context.TODO(), c, newTestItems())
+	// tags are left intact
+	assert.Equal(t, []string{"cat", "dog", "bar"}, model.ToStrings(processed))
+	assert.NotNil(t, ext.Result().Err)
+}
+
+func Test_AppExt_Timeout(t *testing.T) {
+	t.Setenv(helperEnv, "sleep")
+	app := extension.Load("test-app", os.Args[0], "v0.0.1")
+	app.SetExecTimeout(100 * time.Millisecond)
+	_, err := app.Call(context.TODO(), &extension.Request{Hook: extension.HookPostRank})
+	assert.ErrorContains(t, err, "timed out")
+}
+
+func Test_AppExt_Canceled(t *testing.T) {
+	ext := newTestAppExt(t, "drop")
+	c := config.New(config.Limit(5), config.Extensions([]extension.Extension{ext}))
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	processed := Run(ctx, c, newTestItems())
+	// App isn't executed, hence tags are left intact
+	assert.Equal(t, []string{"cat", "dog", "bar"}, model.ToStrings(processed))
+	assert.ErrorIs(t, ext.Result().Err, context.Canceled)
+}
+
+func Test_AppExt_Runs(t *testing.T) {
+	ext := newTestAppExt(t, "fail")
+	c := config.New(config.Limit(5), config.Extensions([]extension.Extension{ext}))
+	Run(context.TODO(), c, newTestItems())
+	assert.NotNil(t, ext.Result().Err)
+
+	// results of the previous run are reset
+	t.Setenv(helperEnv, "drop")
+	Run(context.TODO(), c, newTestItems())
+	res := ext.Result()
+	assert.Nil(t, res.Err)
+	assert.Equal(t, 3.0, res.Data[extension.HookPreRank])
+
+	// concurrent runs share the extension, while its results are collected per run
+	t.Setenv(helperEnv, "source")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(fail bool) {
+			defer wg.Done()
+			cfg := c.Clone()
+			cfg.Source = "drop"
+			if fail {
+				cfg.Source = "fail"
+			}
+			ctx := extension.WithRun(context.TODO())
+			Run(ctx, cfg, newTestItems())
+			_ = ext.Result()
+
+			res := extension.RunResults(ctx, cfg.Extensions)["test-app"]["v0.0.1"]
+			assert.Equal(t, fail, res.Err != nil)
+			if !fail {
+				assert.Equal(t, 3.0, res.Data[extension.HookPreRank])
+				assert.Equal(t, 2.0, res.Data[extension.HookPostRank])
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+}
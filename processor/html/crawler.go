@@ -1,6 +1,7 @@
 package html
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -13,6 +14,7 @@ import (
 	"github.com/zoomio/inout"
 
 	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/safeguard"
 )
 
 const (
@@ -20,7 +22,7 @@ const (
 	crwlBadThresholdInterval = 1500
 )
 
-type parseFunc func(io.Reader, *config.Config, []HTMLExt, *webCrawler) *HTMLContents
+type parseFunc func(context.Context, io.Reader, *config.Config, []HTMLExt, *webCrawler) *HTMLContents
 
 type parseOut struct {
 	cnt *HTMLContents
@@ -35,7 +37,12 @@ type crwlStats struct {
 }
 
 type webCrawler struct {
+	ctx context.Context
 	cfg *config.Config
+	// pageCfg is a snapshot of the config for the crawled pages,
+	// each page gets its own copy, since parsing might change the language.
+	pageCfg *config.Config
+	guard   *safeguard.Guard
 	parseFunc
 	dataCh  chan *parseOut
 	stopCh  chan struct{}
@@ -48,16 +55,24 @@ type webCrawler struct {
 	exts    []HTMLExt
 }
 
-func newWebCrawler(parse parseFunc, exts []HTMLExt, source string, verbose bool) (*webCrawler, error) {
-	u, err := url.Parse(source)
+func newWebCrawler(ctx context.Context, parse parseFunc, cfg *config.Config, exts []HTMLExt) (*webCrawler, error) {
+	u, err := url.Parse(cfg.Source)
 	if err != nil {
 		return nil, err
 	}
+	var guard *safeguard.Guard
+	if safeguard.Enabled(cfg) {
+		guard = safeguard.New(cfg)
+	}
 	var wg sync.WaitGroup
 	var links sync.Map
 	var docs sync.Map
 	var av atomic.Value
 	return &webCrawler{
+		ctx:       ctx,
+		cfg:       cfg,
+		pageCfg:   cfg.Clone(),
+		guard:     guard,
 		parseFunc: parse,
 		dataCh:    make(chan *parseOut, 5),
 		stopCh:    make(chan struct{}),
@@ -66,7 +81,7 @@ func newWebCrawler(parse parseFunc, exts []HTMLExt, source string, verbose bool)
 		links:     &links,
 		docs:      &docs,
 		domain:    toDomain(u),
-		verbose:   verbose,
+		verbose:   cfg.Verbose,
 		exts:      exts,
 	}, nil
 }
@@ -79,14 +94,14 @@ func (c *webCrawler) setStats(sts *crwlStats) {
 	c.stats.Store(sts)
 }
 
+// run parses given document and the documents it links to,
+// crawling stops once the context is done.
 func (c *webCrawler) run(r io.Reader) *HTMLContents {
-	defer close(c.dataCh)
-
 	c.setStats(&crwlStats{
 		start: time.Now(),
 	})
 
-	result := c.parseFunc(r, c.cfg, c.exts, c)
+	result := c.parseFunc(c.ctx, r, c.cfg, c.exts, c)
 
 	// waiter
 	go func(stopCh chan struct{}, wg *sync.WaitGroup) {
@@ -112,6 +127,9 @@ func (c *webCrawler) run(r io.Reader) *HTMLContents {
 			select {
 			case <-crwl.stopCh:
 				return
+			case <-crwl.ctx.Done():
+				go crwl.drain()
+				return
 			default:
 			}
 
@@ -121,6 +139,9 @@ func (c *webCrawler) run(r io.Reader) *HTMLContents {
 			select {
 			case <-crwl.stopCh:
 				return
+			case <-crwl.ctx.Done():
+				go crwl.drain()
+				return
 			case value := <-crwl.dataCh:
 				if value.err == nil {
 					result.lines = append(result.lines, value.cnt.lines...)
@@ -135,12 +156,30 @@ func (c *webCrawler) run(r io.Reader) *HTMLContents {
 	return result
 }
 
+// drain releases pending senders once the receiver is gone,
+// so that the waiter could finish.
+func (c *webCrawler) drain() {
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-c.dataCh:
+			c.wg.Done()
+		}
+	}
+}
+
 func (c *webCrawler) crawl(href string) {
 	c.wg.Add(1)
 	go c.schedule(href)
 }
 
 func (c *webCrawler) schedule(href string) {
+	if c.ctx.Err() != nil {
+		c.trySend(&parseOut{err: c.ctx.Err()})
+		return
+	}
+
 	// add domain if relative link
 	if strings.HasPrefix(href, "/") {
 		href = c.domain + href
@@ -168,13 +207,13 @@ func (c *webCrawler) schedule(href string) {
 	}
 	c.links.Store(src, true)
 
-	r, err := inout.NewInOut(context.TODO(), inout.Source(src), inout.Verbose(c.verbose))
+	r, err := c.fetch(src)
 	if err != nil {
 		c.trySend(&parseOut{err: err})
 		return
 	}
 
-	cnt := c.parseFunc(&r, c.cfg, c.exts, c)
+	cnt := c.parseFunc(c.ctx, r, c.pageCfg.Clone(), c.exts, c)
 	h := fmt.Sprintf("%x", cnt.hash())
 
 	// skip visited docs
@@ -191,6 +230,21 @@ func (c *webCrawler) schedule(href string) {
 	c.trySend(&parseOut{cnt: cnt})
 }
 
+func (c *webCrawler) fetch(src string) (io.Reader, error) {
+	if c.guard != nil {
+		bs, err := c.guard.Get(c.ctx, src, c.cfg.UserAgent)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewReader(bs), nil
+	}
+	r, err := inout.NewInOut(c.ctx, inout.Source(src), inout.Verbose(c.verbose))
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
 func (c *webCrawler) trySend(out *parseOut) {
 	// try to exit the sender goroutine
 	// as early as possible.
@@ -198,6 +252,9 @@ func (c *webCrawler) trySend(out *parseOut) {
 	case <-c.stopCh:
 		c.wg.Done()
 		return
+	case <-c.ctx.Done():
+		c.wg.Done()
+		return
 	default:
 		sts := c.getStats()
 
@@ -234,7 +291,12 @@ func (c *webCrawler) trySend(out *parseOut) {
 			})
 		}
 
-		c.dataCh <- out
+		// receiver is gone once the context is done
+		select {
+		case c.dataCh <- out:
+		case <-c.ctx.Done():
+			c.wg.Done()
+		}
 	}
 }
 
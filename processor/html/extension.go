@@ -1,6 +1,7 @@
 package html
 
 import (
+	"context"
 	"fmt"
 
 	"golang.org/x/net/html"
@@ -25,6 +26,15 @@ type HTMLExtParseTag interface {
 	ParseTag(cfg *config.Config, token *html.Token, lineIdx int, cnts *HTMLContents) (bool, error)
 }
 
+// HTMLExtParseTagContext is HTMLExtParseTag, which receives context of the run,
+// it is preferred over HTMLExtParseTag.
+type HTMLExtParseTagContext interface {
+	HTMLExt
+
+	// ParseTagContext returns true in case if the contents have been appended and false otherwise.
+	ParseTagContext(ctx context.Context, cfg *config.Config, token *html.Token, lineIdx int, cnts *HTMLContents) (bool, error)
+}
+
 // HTMLExtParseText executed at the HTML parsing phase when dealing with the text inside an HTML tag.
 type HTMLExtParseText interface {
 	HTMLExt
@@ -39,6 +49,13 @@ type HTMLExtTagify interface {
 	Tagify(cfg *config.Config, line *HTMLLine, tokenIndex map[string]*model.Tag) error
 }
 
+// HTMLExtTagifyContext is HTMLExtTagify, which receives context of the run,
+// it is preferred over HTMLExtTagify.
+type HTMLExtTagifyContext interface {
+	HTMLExt
+	TagifyContext(ctx context.Context, cfg *config.Config, line *HTMLLine, tokenIndex map[string]*model.Tag) error
+}
+
 func NewHTMLParseEndError() *HTMLParseEndError {
 	return &HTMLParseEndError{}
 }
@@ -61,14 +78,19 @@ func extHTML(exts []extension.Extension) []HTMLExt {
 	return res
 }
 
-func extParseTag(cfg *config.Config, exts []HTMLExt, token *html.Token, lineIdx int, cnts *HTMLContents) (bool, error) {
+func extParseTag(ctx context.Context, cfg *config.Config, exts []HTMLExt, token *html.Token, lineIdx int, cnts *HTMLContents) (bool, error) {
 	var appended bool
 	for _, v := range exts {
-		e, ok := v.(HTMLExtParseTag)
-		if !ok {
+		var ok bool
+		var err error
+		switch e := v.(type) {
+		case HTMLExtParseTagContext:
+			ok, err = e.ParseTagContext(ctx, cfg, token, lineIdx, cnts)
+		case HTMLExtParseTag:
+			ok, err = e.ParseTag(cfg, token, lineIdx, cnts)
+		default:
 			continue
 		}
-		ok, err := e.ParseTag(cfg, token, lineIdx, cnts)
 		if err != nil {
 			if cfg.Verbose {
 				fmt.Printf("error in parsing HTML tag %q in %q %s: %v\n", token.DataAtom.String(), v.Name(), v.Version(), err)
@@ -99,13 +121,17 @@ func extParseText(cfg *config.Config, exts []HTMLExt, tagName, text string, line
 	return nil
 }
 
-func extTagify(cfg *config.Config, exts []HTMLExt, line *HTMLLine, tokenIndex map[string]*model.Tag) {
+func extTagify(ctx context.Context, cfg *config.Config, exts []HTMLExt, line *HTMLLine, tokenIndex map[string]*model.Tag) {
 	for _, v := range exts {
-		e, ok := v.(HTMLExtTagify)
-		if !ok {
+		var err error
+		switch e := v.(type) {
+		case HTMLExtTagifyContext:
+			err = e.TagifyContext(ctx, cfg, line, tokenIndex)
+		case HTMLExtTagify:
+			err = e.Tagify(cfg, line, tokenIndex)
+		default:
 			continue
 		}
-		err := e.Tagify(cfg, line, tokenIndex)
 		if err != nil && cfg.Verbose {
 			fmt.Printf("error in tagifying %q %s: %v\n", v.Name(), v.Version(), err)
 		}
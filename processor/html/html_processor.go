@@ -1,6 +1,7 @@
 package html
 
 import (
+	"context"
 	"crypto/sha512"
 	"fmt"
 	"io"
@@ -10,8 +11,10 @@ import (
 	"golang.org/x/net/html/atom"
 
 	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/dedup"
 	"github.com/zoomio/tagify/extension"
 	"github.com/zoomio/tagify/model"
+	"github.com/zoomio/tagify/processor/keywords"
 	"github.com/zoomio/tagify/processor/util"
 )
 
@@ -113,7 +116,7 @@ func isSameDomain(href, domain string) bool {
 // Result:
 //
 //	foo: 2 + 1 = 3, story: 2, management: 1 + 1 = 2, skills: 1 + 1 = 2.
-var ProcessHTML model.ProcessFunc = func(c *config.Config, reader io.ReadCloser) *model.Result {
+var ProcessHTML model.ProcessFunc = func(ctx context.Context, c *config.Config, reader io.ReadCloser) *model.Result {
 
 	defer reader.Close()
 
@@ -131,9 +134,7 @@ var ProcessHTML model.ProcessFunc = func(c *config.Config, reader io.ReadCloser)
 		c.TagWeights = defaultTagWeights
 	}
 	if c.ExtraTagWeights != nil {
-		for k, v := range c.ExtraTagWeights {
-			c.TagWeights[k] = v
-		}
+		c.TagWeights = config.MergeTagWeights(c.TagWeights, c.ExtraTagWeights)
 	}
 
 	// if c.Verbose {
@@ -142,13 +143,17 @@ var ProcessHTML model.ProcessFunc = func(c *config.Config, reader io.ReadCloser)
 
 	if c.FullSite && c.Source != "" {
 		var crawler *webCrawler
-		crawler, err = newWebCrawler(parseFn, exts, c.Source, c.Verbose)
+		crawler, err = newWebCrawler(ctx, parseFn, c, exts)
 		if err != nil {
 			return model.ErrResult(err)
 		}
 		contents = crawler.run(reader)
 	} else {
-		contents = parseFn(reader, c, exts, nil)
+		contents = parseFn(ctx, reader, c, exts, nil)
+	}
+
+	if ctx.Err() != nil {
+		return model.ErrResult(ctx.Err())
 	}
 
 	if c.Verbose {
@@ -163,21 +168,35 @@ var ProcessHTML model.ProcessFunc = func(c *config.Config, reader io.ReadCloser)
 		return model.EmptyResult()
 	}
 
-	tags, title := tagifyHTML(contents, c, exts)
+	sig := util.NewSigner(c)
+	kw := keywords.New(c)
+	sections := util.NewSections(c)
+	tags, title := tagifyHTML(ctx, contents, c, exts, sig, kw, sections)
+	if ctx.Err() != nil {
+		return model.ErrResult(ctx.Err())
+	}
+	if kw != nil {
+		tags = kw.Tags()
+	}
+
+	meta := &model.Meta{
+		ContentType: config.HTML,
+		DocTitle:    title,
+		DocHash:     fmt.Sprintf("%x", contents.hash()),
+		Lang:        c.Lang,
+	}
+	sig.Sign(meta)
+	sections.Meta(meta)
 
 	return &model.Result{
-		Meta: &model.Meta{
-			ContentType: config.HTML,
-			DocTitle:    title,
-			DocHash:     fmt.Sprintf("%x", contents.hash()),
-			Lang:        c.Lang,
-		},
+		Meta:       meta,
 		RawTags:    tags,
-		Extensions: extension.MapResults(c.Extensions),
+		Extensions: extension.RunResults(ctx, c.Extensions),
 	}
 }
 
-func ParseHTML(reader io.Reader, cfg *config.Config, exts []HTMLExt, c *webCrawler) *HTMLContents {
+// ParseHTML parses HTML into the contents, parsing stops once the context is done.
+func ParseHTML(ctx context.Context, reader io.Reader, cfg *config.Config, exts []HTMLExt, c *webCrawler) *HTMLContents {
 	contents := &HTMLContents{lines: make([]*HTMLLine, 0), htmlTagWeights: cfg.TagWeights}
 	parser := &htmlParser{}
 
@@ -196,6 +215,10 @@ func ParseHTML(reader io.Reader, cfg *config.Config, exts []HTMLExt, c *webCrawl
 
 	z := html.NewTokenizer(reader)
 	for {
+		if util.Done(ctx) {
+			return contents
+		}
+
 		tt := z.Next()
 
 		switch tt {
@@ -214,7 +237,7 @@ func ParseHTML(reader io.Reader, cfg *config.Config, exts []HTMLExt, c *webCrawl
 			_, hasWeight := cfg.TagWeights[token.Data]
 			_, isExcluded := cfg.ExcludeTags[token.Data]
 			if (hasWeight || cfg.AllTagWeights) && !isExcluded {
-				_, err := extParseTag(cfg, exts, &token, parser.lineIndex, contents)
+				_, err := extParseTag(ctx, cfg, exts, &token, parser.lineIndex, contents)
 				if err != nil {
 					switch err.(type) {
 					case *HTMLParseEndError:
@@ -274,7 +297,7 @@ func ParseHTML(reader io.Reader, cfg *config.Config, exts []HTMLExt, c *webCrawl
 			}
 
 			// allow for extensions
-			ok, err := extParseTag(cfg, exts, &token, parser.lineIndex, contents)
+			ok, err := extParseTag(ctx, cfg, exts, &token, parser.lineIndex, contents)
 			if err != nil {
 				switch err.(type) {
 				case *HTMLParseEndError:
@@ -360,16 +383,27 @@ func ParseHTML(reader io.Reader, cfg *config.Config, exts []HTMLExt, c *webCrawl
 	}
 }
 
-func tagifyHTML(contents *HTMLContents, cfg *config.Config,
-	exts []HTMLExt) (tokenIndex map[string]*model.Tag, pageTitle string) {
+func tagifyHTML(ctx context.Context, contents *HTMLContents, cfg *config.Config,
+	exts []HTMLExt, sig *dedup.Signer, kw keywords.Extractor, sections *util.Sections) (tokenIndex map[string]*model.Tag, pageTitle string) {
 
 	tokenIndex = map[string]*model.Tag{}
 
 	var docsCount int
+	pos := util.NewPosition(cfg)
+	// lede is the first paragraph after the h1 heading
+	var h1Seen, ledeSeen bool
 
 	for _, l := range contents.lines {
 		s := string(l.data)
 
+		lede := h1Seen && !ledeSeen && l.tag == atom.P.String()
+		if lede {
+			ledeSeen = true
+		}
+		if l.tag == atom.H1.String() {
+			h1Seen = true
+		}
+
 		// Title tags have special treatment
 		// if (l.tag == atom.Title.String() || l.tag == atom.H1.String()) && len(pageTitle) < len(s) {
 		if (l.tag == atom.Title.String() || l.tag == atom.H1.String()) && len(pageTitle) == 0 {
@@ -384,8 +418,13 @@ func tagifyHTML(contents *HTMLContents, cfg *config.Config,
 		// 	continue
 		// }
 
-		sentences := l.sentences()
+		lc := sections.Config(l.data, cfg)
+		sentences := l.sentences(cfg)
 		for _, snt := range sentences {
+			if util.Done(ctx) {
+				return
+			}
+
 			// skip random non-text related tags
 			if cfg.ContentOnly && !isHTMLContent(snt.tag) {
 				continue
@@ -397,6 +436,10 @@ func tagifyHTML(contents *HTMLContents, cfg *config.Config,
 
 			docsCount++
 			visited := map[string]bool{}
+			if kw != nil {
+				kw.Add(snt.data)
+			}
+			posWeight := pos.Next(lede)
 
 			snt.forEach(func(i int, p *htmlPart) {
 				var weight float64
@@ -405,17 +448,20 @@ func tagifyHTML(contents *HTMLContents, cfg *config.Config,
 				} else {
 					weight = cfg.TagWeights[p.tag]
 				}
+				weight *= posWeight
 
-				tokens := util.SplitToTokens(snt.pData(p), cfg)
+				tokens, entities := util.SplitToTokensWithEntities(snt.pData(p), i == 0, lc)
+				sig.Add(tokens...)
 
-				for _, token := range tokens {
+				weights := util.TokenWeights(tokens, lc)
+				for k, token := range tokens {
 					visited[token] = true
 					item, ok := tokenIndex[token]
 					if !ok {
-						item = &model.Tag{Value: token}
+						item = &model.Tag{Value: token, Entity: entities[token]}
 						tokenIndex[token] = item
 					}
-					item.Score += weight
+					item.Score += weight * weights.At(k)
 					item.Count++
 				}
 			})
@@ -423,11 +469,14 @@ func tagifyHTML(contents *HTMLContents, cfg *config.Config,
 			// increment number of appearances in documents for each visited tag
 			for token := range visited {
 				tokenIndex[token].Docs++
+				if cfg.Diversify {
+					tokenIndex[token].Sentences = append(tokenIndex[token].Sentences, docsCount)
+				}
 			}
 		}
 
 		// run extensions if any
-		extTagify(cfg, exts, l, tokenIndex)
+		extTagify(ctx, cfg, exts, l, tokenIndex)
 	}
 
 	// set total number of documents in the text.
@@ -542,6 +591,16 @@ func (l *HTMLLine) String() string {
 	return sb.String()
 }
 
+// Tag returns name of the HTML tag of the line.
+func (l *HTMLLine) Tag() string {
+	return l.tag
+}
+
+// Text returns text of the line.
+func (l *HTMLLine) Text() string {
+	return string(l.data)
+}
+
 func (l *HTMLLine) isEmpty() bool {
 	return len(l.parts) == 0
 }
@@ -562,10 +621,10 @@ func (l *HTMLLine) pData(part *htmlPart) []byte {
 }
 
 // breaksdown an HTML line into a slice of HTML sentences.
-func (l *HTMLLine) sentences() []*HTMLLine {
+func (l *HTMLLine) sentences(cfg *config.Config) []*HTMLLine {
 	ret := []*HTMLLine{}
 	var offset, diff, pDiff, i, j int
-	sents := util.SplitToSentences(l.data)
+	sents := util.SplitToSentencesWith(l.data, cfg)
 	for i < len(l.parts) && j < len(sents) {
 		s := &HTMLLine{tag: l.tag, parts: []*htmlPart{}}
 		ret = append(ret, s)
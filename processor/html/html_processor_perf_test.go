@@ -2,6 +2,7 @@ package html
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"testing"
 
@@ -19,20 +20,21 @@ func BenchmarkParseHTML(b *testing.B) {
 	// setup
 	cfg, contents := setup(vergeHTML)
 	for i := 0; i < b.N; i++ {
-		_, _ = tagifyHTML(contents, cfg, nil)
+		_, _ = tagifyHTML(context.Background(), contents, cfg, nil, nil, nil, nil)
 	}
 }
 
 func BenchmarkParseHTML_chinese(b *testing.B) {
 	cfg, contents := setup(chineseHTML)
 	for i := 0; i < b.N; i++ {
-		_, _ = tagifyHTML(contents, cfg, nil)
+		_, _ = tagifyHTML(context.Background(), contents, cfg, nil, nil, nil, nil)
 	}
 }
 
 func setup(htmlPage []byte) (*config.Config, *HTMLContents) {
 	cfg := &config.Config{TagWeights: defaultTagWeights}
 	contents := ParseHTML(
+		context.Background(),
 		bytes.NewBuffer(htmlPage),
 		&config.Config{TagWeights: defaultTagWeights},
 		nil,
@@ -2,10 +2,12 @@ package html
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/net/html"
@@ -140,7 +142,7 @@ func Test_ProcessHTML(t *testing.T) {
 	for _, tt := range processHTMLTests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := config.New(config.NoStopWords(tt.noStopWords), config.ContentOnly(tt.contentOnly))
-			out := ProcessHTML(cfg, &inputReadCloser{strings.NewReader(tt.in)})
+			out := ProcessHTML(context.Background(), cfg, &inputReadCloser{strings.NewReader(tt.in)})
 			assert.Equal(t, tt.title, out.Meta.DocTitle)
 			assert.Equal(t, tt.hash, out.Meta.DocHash)
 			assert.Equal(t, tt.lang, out.Meta.Lang)
@@ -150,7 +152,7 @@ func Test_ProcessHTML(t *testing.T) {
 }
 
 /* func Test_ProcessHTML_DedupeTitleAndHeading(t *testing.T) {
-	out := ProcessHTML(config.New(config.NoStopWords(true)), &inputReadCloser{bytes.NewReader(doubledTitleHTML)})
+	out := ProcessHTML(context.Background(), config.New(config.NoStopWords(true)), &inputReadCloser{bytes.NewReader(doubledTitleHTML)})
 	assert.Equal(t, "A story about a boy", out.Meta.DocTitle)
 	assert.Equal(t,
 		"4f652c47205d3b922115eef155c484cf81096351696413c86277fa0ed89ebfefe30f81ef6fc6a9d7d654a9292c3cb7aa6f3696052e53c113785a9b1b3be7d4a8",
@@ -159,7 +161,7 @@ func Test_ProcessHTML(t *testing.T) {
 } */
 
 func Test_ProcessHTML_NoSpecificStopWords(t *testing.T) {
-	out := ProcessHTML(config.New(config.NoStopWords(true)), &inputReadCloser{bytes.NewReader(doubledTitleHTML)})
+	out := ProcessHTML(context.Background(), config.New(config.NoStopWords(true)), &inputReadCloser{bytes.NewReader(doubledTitleHTML)})
 	assert.Equal(t, "A story about a boy", out.Meta.DocTitle)
 	assert.Equal(t,
 		"4f652c47205d3b922115eef155c484cf81096351696413c86277fa0ed89ebfefe30f81ef6fc6a9d7d654a9292c3cb7aa6f3696052e53c113785a9b1b3be7d4a8",
@@ -176,6 +178,7 @@ func Test_ParseHTML(t *testing.T) {
 	</html>
 `
 	contents := ParseHTML(
+		context.Background(),
 		&inputReadCloser{strings.NewReader(htmlPage)},
 		&config.Config{TagWeights: defaultTagWeights},
 		nil,
@@ -201,6 +204,7 @@ func Test_ParseHTML(t *testing.T) {
 func Test_ParseReaderHTML_visits_all_tags(t *testing.T) {
 	counter := &testCountingExt{BaseExtension: extension.NewExtension("testCountingExt", "1")}
 	contents := ParseHTML(
+		context.Background(),
 		io.NopCloser(bytes.NewReader(theVergeHTML)),
 		&config.Config{Verbose: false, SkipLang: true, AllTagWeights: true},
 		[]HTMLExt{counter},
@@ -274,3 +278,40 @@ func Test_isSameDomain(t *testing.T) {
 		})
 	}
 }
+
+func Test_ProcessHTML_Cancel(t *testing.T) {
+	doc := "<html><body>" +
+		strings.Repeat("<h2>A story about Jim</h2><p>There was a boy whose name was Jim. His friends were very good to him.</p>", 50000) +
+		"</body></html>"
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	out := ProcessHTML(cancelled, config.New(), &inputReadCloser{strings.NewReader(doc)})
+	assert.ErrorIs(t, out.Err, context.Canceled)
+
+	timed, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	out = ProcessHTML(timed, config.New(), &inputReadCloser{strings.NewReader(doc)})
+	assert.ErrorIs(t, out.Err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func Test_ProcessHTML_PositionWeights(t *testing.T) {
+	const page = `<html><body>
+	<p>Subscribe</p>
+	<h1>Headline</h1>
+	<p>Elections are coming.</p>
+	<p>Weather is fine.</p>
+	</body></html>`
+
+	cfg := config.New(config.NoStopWords(true), config.PositionWeights(config.StepDecay(1, 2)))
+	out := ProcessHTML(context.Background(), cfg, &inputReadCloser{strings.NewReader(page)})
+	assert.Nil(t, out.Err)
+
+	// first sentence & lede after the h1 are boosted
+	p := cfg.TagWeights[atom.P.String()]
+	assert.Equal(t, 2*p, out.RawTags["subscribe"].Score)
+	assert.Equal(t, 2*p, out.RawTags["elections"].Score)
+	assert.Equal(t, p, out.RawTags["weather"].Score)
+}
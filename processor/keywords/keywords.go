@@ -0,0 +1,117 @@
+// Package keywords implements statistical, corpus-free keyword extraction
+// algorithms RAKE & YAKE (see config.Algorithm), which give multi-word
+// candidates with their native scores.
+//
+// Extractors are fed with sentences (see util.SplitToSentences)
+// in the order of the document, stop words of the configuration
+// (or of its language) split candidates.
+package keywords
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/zoomio/stopwords"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/model"
+	"github.com/zoomio/tagify/processor/util"
+)
+
+// MaxWords is the maximum number of words in a candidate.
+const MaxWords = 3
+
+// Extractor collects statistics of the sentences and extracts keywords from them.
+type Extractor interface {
+	// Add adds next sentence of the document.
+	Add(sentence []byte)
+	// Tags returns extracted keywords with their native scores.
+	Tags() map[string]*model.Tag
+}
+
+// New returns extractor for the algorithm of the configuration,
+// nil is returned for the Frequency algorithm.
+func New(cfg *config.Config) Extractor {
+	switch cfg.Algorithm {
+	case config.RAKE:
+		return newRake(cfg)
+	case config.YAKE:
+		return newYake(cfg)
+	default:
+		return nil
+	}
+}
+
+// Extract extracts keywords from the text with the algorithm of the configuration.
+func Extract(text []byte, cfg *config.Config) map[string]*model.Tag {
+	e := New(cfg)
+	if e == nil {
+		return nil
+	}
+	for _, s := range util.SplitToSentencesWith(text, cfg) {
+		e.Add(s)
+	}
+	return e.Tags()
+}
+
+// term is a word of the sentence.
+type term struct {
+	raw   string // as in the text
+	value string // normalized
+	stop  bool
+}
+
+// chunks splits sentence into chunks of terms separated by punctuation & non-words.
+func chunks(sentence []byte, cfg *config.Config, reg *stopwords.Register) [][]term {
+	var out [][]term
+	var cur []term
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, cur)
+			cur = nil
+		}
+	}
+
+	for _, seg := range cfg.Segment(util.NormalizeText(sentence, cfg)) {
+		raw := strings.TrimFunc(string(seg), isBreak)
+		if raw == "" {
+			flush()
+			continue
+		}
+		r, _ := utf8.DecodeRune(seg)
+		if isBreak(r) {
+			flush()
+		}
+
+		value, ok := util.Normalize(strings.ToLower(strings.Replace(raw, "’", "'", -1)), nil)
+		if !ok {
+			flush()
+			continue
+		}
+		cur = append(cur, term{
+			raw:   raw,
+			value: value,
+			stop:  reg.IsStopWord(value) || utf8.RuneCountInString(value) < 2,
+		})
+
+		r, _ = utf8.DecodeLastRune(seg)
+		if isBreak(r) {
+			flush()
+		}
+	}
+	flush()
+	return out
+}
+
+func isBreak(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '\'' && r != '’'
+}
+
+func joinTerms(ts []term) string {
+	vs := make([]string, len(ts))
+	for i, t := range ts {
+		vs[i] = t.value
+	}
+	return strings.Join(vs, " ")
+}
@@ -0,0 +1,93 @@
+package keywords
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/model"
+)
+
+// abstract from the original RAKE paper
+const abstract = `Compatibility of systems of linear constraints over the set of natural numbers.
+Criteria of compatibility of a system of linear Diophantine equations, strict inequations,
+and nonstrict inequations are considered. Upper bounds for components of a minimal set of
+solutions and algorithms of construction of minimal generating sets of solutions for all types
+of systems are given. These criteria and the corresponding algorithms for constructing a minimal
+supporting set of solutions can be used in solving all the considered types of systems and
+systems of mixed types.`
+
+func top(tags map[string]*model.Tag, n int, asc bool) []string {
+	list := make([]*model.Tag, 0, len(tags))
+	for _, t := range tags {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return (list[i].Score < list[j].Score) == asc
+		}
+		return list[i].Value < list[j].Value
+	})
+	if len(list) > n {
+		list = list[:n]
+	}
+	return model.ToStrings(list)
+}
+
+func Test_New(t *testing.T) {
+	assert.Nil(t, New(config.New()))
+	assert.Nil(t, Extract([]byte(abstract), config.New()))
+	assert.IsType(t, &rake{}, New(config.New(config.KeywordAlgorithm(config.RAKE))))
+	assert.IsType(t, &yake{}, New(config.New(config.KeywordAlgorithm(config.YAKE))))
+}
+
+func Test_RAKE(t *testing.T) {
+	tags := Extract([]byte(abstract), config.New(config.KeywordAlgorithm(config.RAKE), config.Language("en")))
+
+	// words of "natural numbers" have degree 2 & frequency 1
+	assert.InDelta(t, 4, tags["natural numbers"].Score, 0.01)
+	assert.ElementsMatch(t, []string{"linear diophantine equations", "minimal generating sets"}, top(tags, 2, false))
+
+	minimalSet := tags["minimal set"]
+	assert.Equal(t, 1, minimalSet.Count)
+	assert.Equal(t, 1, minimalSet.Docs)
+	assert.Equal(t, 10, minimalSet.DocsCount)
+
+	// candidates never contain stop words
+	for v := range tags {
+		assert.NotContains(t, v, " of ")
+	}
+}
+
+func Test_YAKE(t *testing.T) {
+	tags := Extract([]byte(abstract), config.New(config.KeywordAlgorithm(config.YAKE), config.Language("en")))
+
+	// multi-word candidates don't start nor end with stop words
+	assert.Contains(t, tags, "linear diophantine equations")
+	assert.Contains(t, tags, "compatibility of systems")
+	assert.NotContains(t, tags, "of systems")
+	assert.NotContains(t, tags, "the set")
+
+	best := top(tags, 5, true)
+	assert.Contains(t, best, "systems")
+	assert.Contains(t, best, "linear diophantine equations")
+	for _, tag := range tags {
+		assert.Greater(t, tag.Score, 0.0)
+	}
+
+	// works on short texts
+	tags = Extract([]byte("Google acquires Kaggle."), config.New(config.KeywordAlgorithm(config.YAKE), config.Language("en")))
+	assert.Equal(t, []string{"google acquires kaggle"}, top(tags, 1, true))
+	assert.Contains(t, tags, "kaggle")
+}
+
+func Test_Normalize(t *testing.T) {
+	text := []byte("Crème brûlée is a dessert. Everyone likes ﬁne crème brûlée.")
+	for _, alg := range []config.Algorithm{config.RAKE, config.YAKE} {
+		tags := Extract(text, config.New(config.KeywordAlgorithm(alg), config.Language("en"), config.FoldAccents("*")))
+		assert.Contains(t, tags, "creme brulee")
+		assert.NotContains(t, tags, "crème brûlée")
+	}
+}
@@ -0,0 +1,100 @@
+package keywords
+
+import (
+	"github.com/zoomio/stopwords"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/model"
+)
+
+// rake implements RAKE (Rose et al., 2010): candidates are sequences of
+// content words between stop words & punctuation, score of the word is
+// its degree in the co-occurrence graph divided by its frequency,
+// score of the candidate is the sum of the scores of its words.
+type rake struct {
+	cfg *config.Config
+	reg *stopwords.Register
+
+	sentences int
+	freq      map[string]int
+	degree    map[string]int
+	phrases   map[string]*rakePhrase
+}
+
+type rakePhrase struct {
+	words []string
+	count int
+	docs  int
+	last  int   // last sentence the phrase was seen in
+	seen  []int // sentences the phrase was seen in, only for the diversification
+}
+
+func newRake(cfg *config.Config) *rake {
+	return &rake{
+		cfg:     cfg,
+		reg:     cfg.LangStopWords(),
+		freq:    map[string]int{},
+		degree:  map[string]int{},
+		phrases: map[string]*rakePhrase{},
+	}
+}
+
+func (r *rake) Add(sentence []byte) {
+	r.sentences++
+	for _, chunk := range chunks(sentence, r.cfg, r.reg) {
+		start := 0
+		for i := 0; i <= len(chunk); i++ {
+			if i < len(chunk) && !chunk[i].stop {
+				continue
+			}
+			r.addPhrase(chunk[start:i])
+			start = i + 1
+		}
+	}
+}
+
+func (r *rake) addPhrase(ts []term) {
+	if len(ts) == 0 || len(ts) > MaxWords {
+		return
+	}
+	value := joinTerms(ts)
+	p, ok := r.phrases[value]
+	if !ok {
+		p = &rakePhrase{words: make([]string, len(ts))}
+		for i, t := range ts {
+			p.words[i] = t.value
+		}
+		r.phrases[value] = p
+	}
+	p.count++
+	if p.last != r.sentences {
+		p.docs++
+		p.last = r.sentences
+		if r.cfg.Diversify {
+			p.seen = append(p.seen, r.sentences)
+		}
+	}
+	for _, t := range ts {
+		r.freq[t.value]++
+		r.degree[t.value] += len(ts)
+	}
+}
+
+func (r *rake) Tags() map[string]*model.Tag {
+	tags := make(map[string]*model.Tag, len(r.phrases))
+	for value, p := range r.phrases {
+		var score float64
+		for _, w := range p.words {
+			score += float64(r.degree[w]) / float64(r.freq[w])
+		}
+		tags[value] = &model.Tag{
+			Value:     value,
+			Score:     score,
+			Count:     p.count,
+			Docs:      p.docs,
+			DocsCount: r.sentences,
+			Sentences: p.seen,
+		}
+	}
+	return tags
+}
@@ -0,0 +1,219 @@
+package keywords
+
+import (
+	"math"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/zoomio/stopwords"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/model"
+)
+
+// yake implements YAKE (Campos et al., 2020): score of the word combines
+// its casing, position, frequency, relatedness to the context and spread
+// over the sentences, candidates are n-grams of up to MaxWords words,
+// which don't start nor end with stop words. Lower scores are better.
+type yake struct {
+	cfg *config.Config
+	reg *stopwords.Register
+
+	sentences  int
+	terms      map[string]*yakeTerm
+	candidates map[string]*yakeCandidate
+}
+
+type yakeTerm struct {
+	tf, upper, acronym int
+	stop               bool
+	positions          []int // sentences of the occurrences, in order
+	left, right        map[string]int
+}
+
+type yakeCandidate struct {
+	terms []string
+	count int
+	docs  int
+	last  int
+	seen  []int // sentences the candidate was seen in, only for the diversification
+}
+
+func newYake(cfg *config.Config) *yake {
+	return &yake{
+		cfg:        cfg,
+		reg:        cfg.LangStopWords(),
+		terms:      map[string]*yakeTerm{},
+		candidates: map[string]*yakeCandidate{},
+	}
+}
+
+func (y *yake) Add(sentence []byte) {
+	idx := y.sentences
+	y.sentences++
+
+	first := true
+	for _, chunk := range chunks(sentence, y.cfg, y.reg) {
+		for i, t := range chunk {
+			st, ok := y.terms[t.value]
+			if !ok {
+				st = &yakeTerm{stop: t.stop, left: map[string]int{}, right: map[string]int{}}
+				y.terms[t.value] = st
+			}
+			st.tf++
+			st.positions = append(st.positions, idx)
+			if isAcronym(t.raw) {
+				st.acronym++
+			} else if !first && isCapitalized(t.raw) {
+				st.upper++
+			}
+			first = false
+
+			if i > 0 && !chunk[i-1].stop {
+				st.left[chunk[i-1].value]++
+			}
+			if i < len(chunk)-1 && !chunk[i+1].stop {
+				st.right[chunk[i+1].value]++
+			}
+
+			// candidates ending with the current term
+			if t.stop {
+				continue
+			}
+			for n := 1; n <= MaxWords && i-n+1 >= 0; n++ {
+				ts := chunk[i-n+1 : i+1]
+				if ts[0].stop {
+					continue
+				}
+				y.addCandidate(ts, idx)
+			}
+		}
+	}
+}
+
+func (y *yake) addCandidate(ts []term, idx int) {
+	value := joinTerms(ts)
+	c, ok := y.candidates[value]
+	if !ok {
+		c = &yakeCandidate{terms: make([]string, len(ts)), last: -1}
+		for i, t := range ts {
+			c.terms[i] = t.value
+		}
+		y.candidates[value] = c
+	}
+	c.count++
+	if c.last != idx {
+		c.docs++
+		c.last = idx
+		if y.cfg.Diversify {
+			c.seen = append(c.seen, idx)
+		}
+	}
+}
+
+func (y *yake) Tags() map[string]*model.Tag {
+	scores := y.termScores()
+	tags := make(map[string]*model.Tag, len(y.candidates))
+	for value, c := range y.candidates {
+		prod, sum := 1.0, 0.0
+		for _, t := range c.terms {
+			// stop words inside of the candidates are neutral
+			if s, ok := scores[t]; ok {
+				prod *= s
+				sum += s
+			}
+		}
+		tags[value] = &model.Tag{
+			Value:     value,
+			Score:     prod / (float64(c.count) * (1 + sum)),
+			Count:     c.count,
+			Docs:      c.docs,
+			DocsCount: y.sentences,
+			Sentences: c.seen,
+		}
+	}
+	return tags
+}
+
+// termScores returns scores of the non stop words.
+func (y *yake) termScores() map[string]float64 {
+	var tfs []float64
+	var maxTF float64
+	for _, t := range y.terms {
+		if !t.stop {
+			tfs = append(tfs, float64(t.tf))
+			maxTF = math.Max(maxTF, float64(t.tf))
+		}
+	}
+	mean, std := meanStd(tfs)
+
+	scores := make(map[string]float64, len(tfs))
+	for value, t := range y.terms {
+		if t.stop {
+			continue
+		}
+		tf := float64(t.tf)
+		tCase := float64(max(t.upper, t.acronym)) / (1 + math.Log(tf))
+		tPos := math.Log(math.Log(3 + float64(t.positions[len(t.positions)/2])))
+		tFreq := tf / (mean + std)
+		tRel := 1 + (spread(t.left)+spread(t.right))*tf/maxTF
+		tDif := float64(distinct(t.positions)) / float64(y.sentences)
+		scores[value] = tRel * tPos / (tCase + tFreq/tRel + tDif/tRel)
+	}
+	return scores
+}
+
+// spread is the ratio of the distinct neighbours to all of them.
+func spread(neighbours map[string]int) float64 {
+	var total int
+	for _, n := range neighbours {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(len(neighbours)) / float64(total)
+}
+
+// distinct counts distinct values in the sorted slice.
+func distinct(sorted []int) int {
+	var n int
+	for i, v := range sorted {
+		if i == 0 || v != sorted[i-1] {
+			n++
+		}
+	}
+	return n
+}
+
+func meanStd(vs []float64) (mean, std float64) {
+	if len(vs) == 0 {
+		return 0, 0
+	}
+	for _, v := range vs {
+		mean += v
+	}
+	mean /= float64(len(vs))
+	for _, v := range vs {
+		std += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(std / float64(len(vs)))
+}
+
+func isAcronym(s string) bool {
+	var letters int
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			if !unicode.IsUpper(r) {
+				return false
+			}
+			letters++
+		}
+	}
+	return letters > 1
+}
+
+func isCapitalized(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsUpper(r)
+}
@@ -3,6 +3,7 @@ package md
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/sha512"
 	"fmt"
 	"io"
@@ -10,7 +11,9 @@ import (
 	"strings"
 
 	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/dedup"
 	"github.com/zoomio/tagify/model"
+	"github.com/zoomio/tagify/processor/keywords"
 	"github.com/zoomio/tagify/processor/util"
 )
 
@@ -99,14 +102,17 @@ func (t mdType) String() string {
 }
 
 // ProcessMD parses given Markdown document input into a slice of tags.
-var ProcessMD model.ProcessFunc = func(c *config.Config, in io.ReadCloser) *model.Result {
+var ProcessMD model.ProcessFunc = func(ctx context.Context, c *config.Config, in io.ReadCloser) *model.Result {
 
 	if c.Verbose {
 		fmt.Println("--> parsing Markdown...")
 	}
 
 	defer in.Close()
-	contents := ParseMD(in, c)
+	contents := ParseMD(ctx, in, c)
+	if ctx.Err() != nil {
+		return model.ErrResult(ctx.Err())
+	}
 
 	if c.Verbose {
 		fmt.Println("--> parsed")
@@ -117,29 +123,41 @@ var ProcessMD model.ProcessFunc = func(c *config.Config, in io.ReadCloser) *mode
 		c.TagWeights = defaultTagWeights
 	}
 	if c.ExtraTagWeights != nil {
-		for k, v := range c.ExtraTagWeights {
-			c.TagWeights[k] = v
-		}
+		c.TagWeights = config.MergeTagWeights(c.TagWeights, c.ExtraTagWeights)
 	}
 
 	// if c.Verbose {
 	// 	fmt.Printf("using configuration: %#v\n", c)
 	// }
 
-	tags, title := tagifyMD(contents, c)
+	sig := util.NewSigner(c)
+	kw := keywords.New(c)
+	sections := util.NewSections(c)
+	tags, title := tagifyMD(ctx, contents, c, sig, kw, sections)
+	if ctx.Err() != nil {
+		return model.ErrResult(ctx.Err())
+	}
+	if kw != nil {
+		tags = kw.Tags()
+	}
+
+	meta := &model.Meta{
+		ContentType: config.Markdown,
+		DocTitle:    title,
+		DocHash:     fmt.Sprintf("%x", contents.hash()),
+		Lang:        c.Lang,
+	}
+	sig.Sign(meta)
+	sections.Meta(meta)
 
 	return &model.Result{
 		RawTags: tags,
-		Meta: &model.Meta{
-			ContentType: config.Markdown,
-			DocTitle:    title,
-			DocHash:     fmt.Sprintf("%x", contents.hash()),
-			Lang:        c.Lang,
-		},
+		Meta:    meta,
 	}
 }
 
-func ParseMD(reader io.Reader, cfg *config.Config) *MDContents {
+// ParseMD parses Markdown into the contents, parsing stops once the context is done.
+func ParseMD(ctx context.Context, reader io.Reader, cfg *config.Config) *MDContents {
 
 	contents := &MDContents{lines: make([]*mdLine, 0)}
 	scanner := bufio.NewScanner(reader)
@@ -158,6 +176,9 @@ func ParseMD(reader io.Reader, cfg *config.Config) *MDContents {
 	index := -1
 
 	for scanner.Scan() {
+		if util.Done(ctx) {
+			return contents
+		}
 
 		if len(contents.lines) > 0 && len(contents.lines[index].data) > 0 {
 			l := contents.lines[index]
@@ -167,6 +188,7 @@ func ParseMD(reader io.Reader, cfg *config.Config) *MDContents {
 		line := bytes.TrimSpace(scanner.Bytes())
 		// skip empty line
 		if len(line) == 0 {
+			contents.block++
 			continue
 		}
 
@@ -225,11 +247,25 @@ func ParseMD(reader io.Reader, cfg *config.Config) *MDContents {
 	return contents
 }
 
-func tagifyMD(contents *MDContents, c *config.Config) (tokenIndex map[string]*model.Tag, pageTitle string) {
+func tagifyMD(ctx context.Context, contents *MDContents, c *config.Config, sig *dedup.Signer, kw keywords.Extractor,
+	sections *util.Sections) (tokenIndex map[string]*model.Tag, pageTitle string) {
 	tokenIndex = make(map[string]*model.Tag)
 	var docsCount int
+	pos := util.NewPosition(c)
+	// lede is the first paragraph after the level 1 heading
+	var h1Seen bool
+	ledeBlock := -1
 
 	for _, line := range contents.lines {
+		isText := !isMDHeading(line.tag) && line.tag != blockquote
+		if h1Seen && ledeBlock < 0 && isText {
+			ledeBlock = line.block
+		}
+		lede := isText && line.block == ledeBlock
+		if line.tag == heading1 {
+			h1Seen = true
+		}
+
 		// skip empty lines
 		if len(line.parts) == 0 {
 			continue
@@ -241,30 +277,42 @@ func tagifyMD(contents *MDContents, c *config.Config) (tokenIndex map[string]*mo
 			pageTitle = s
 		}
 
-		sentences := line.sentences()
+		lc := sections.Config(line.data, c)
+		sentences := line.sentences(c)
 		for _, snt := range sentences {
+			if util.Done(ctx) {
+				return
+			}
+
 			if len(snt.data) == 0 {
 				continue
 			}
 
 			docsCount++
 			visited := map[string]bool{}
+			if kw != nil {
+				kw.Add(snt.data)
+			}
+
+			posWeight := pos.Next(lede)
 
 			snt.forEach(func(i int, p *mdPart) {
-				weight := c.TagWeights[p.tag.String()]
-				tokens := util.SplitToTokens(snt.pData(p), c)
+				weight := c.TagWeights[p.tag.String()] * posWeight
+				tokens, entities := util.SplitToTokensWithEntities(snt.pData(p), i == 0, lc)
+				sig.Add(tokens...)
 				if c.Verbose && len(tokens) > 0 {
 					fmt.Printf("<%s>: %v\n", line.tag.String(), tokens)
 				}
 
-				for _, token := range tokens {
+				weights := util.TokenWeights(tokens, lc)
+				for k, token := range tokens {
 					visited[token] = true
 					item, ok := tokenIndex[token]
 					if !ok {
-						item = &model.Tag{Value: token}
+						item = &model.Tag{Value: token, Entity: entities[token]}
 						tokenIndex[token] = item
 					}
-					item.Score += weight
+					item.Score += weight * weights.At(k)
 					item.Count++
 				}
 			})
@@ -272,6 +320,9 @@ func tagifyMD(contents *MDContents, c *config.Config) (tokenIndex map[string]*mo
 			// increment number of appearances in documents for each visited tag
 			for token := range visited {
 				tokenIndex[token].Docs++
+				if c.Diversify {
+					tokenIndex[token].Sentences = append(tokenIndex[token].Sentences, docsCount)
+				}
 			}
 		}
 	}
@@ -334,11 +385,12 @@ type mdPartHandler struct {
 // MDContents stores text from target tags.
 type MDContents struct {
 	lines []*mdLine
+	block int // current block of the lines
 }
 
 func (cnt *MDContents) append(index int, tag mdType, data []byte) {
 	for len(cnt.lines) <= index {
-		cnt.lines = append(cnt.lines, &mdLine{tag: tag, parts: make([]*mdPart, 0)})
+		cnt.lines = append(cnt.lines, &mdLine{tag: tag, parts: make([]*mdPart, 0), block: cnt.block})
 	}
 	line := cnt.lines[index]
 	line.add(tag, data)
@@ -388,6 +440,7 @@ type mdLine struct {
 	tag   mdType
 	parts []*mdPart
 	data  []byte
+	block int // lines of the same block (e.g. paragraph) aren't separated by empty lines
 }
 
 func (l *mdLine) add(tag mdType, data []byte) {
@@ -418,10 +471,10 @@ func (l *mdLine) forEach(it func(i int, p *mdPart)) {
 }
 
 // breaksdown a markdown line into a slice of markdown sentences.
-func (l *mdLine) sentences() []*mdLine {
+func (l *mdLine) sentences(cfg *config.Config) []*mdLine {
 	ret := []*mdLine{}
 	var offset, diff, pDiff, i, j int
-	sents := util.SplitToSentences(l.data)
+	sents := util.SplitToSentencesWith(l.data, cfg)
 	for i < len(l.parts) && j < len(sents) {
 		s := &mdLine{tag: l.tag, parts: []*mdPart{}}
 		ret = append(ret, s)
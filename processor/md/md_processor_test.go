@@ -1,9 +1,11 @@
 package md
 
 import (
+	"context"
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -84,7 +86,7 @@ var parseMDTests = []struct {
 func Test_ParseMD(t *testing.T) {
 	for _, tt := range parseMDTests {
 		t.Run(tt.name, func(t *testing.T) {
-			out := ProcessMD(config.New(config.NoStopWords(tt.noStopWords)), &inputReadCloser{strings.NewReader(tt.text)})
+			out := ProcessMD(context.Background(), config.New(config.NoStopWords(tt.noStopWords)), &inputReadCloser{strings.NewReader(tt.text)})
 			assert.Equal(t, tt.title, out.Meta.DocTitle)
 			assert.Equal(t, tt.hash, out.Meta.DocHash)
 			assert.ElementsMatch(t, tt.tags, model.ToStrings(out.Flatten()))
@@ -101,12 +103,12 @@ func Test_mdContents_sentences(t *testing.T) {
 	}
 
 	l1 := contents.lines[0]
-	ss1 := l1.sentences()
+	ss1 := l1.sentences(config.New())
 	assert.Len(t, ss1, 1)
 	assert.Equal(t, "There was a boy", string(ss1[0].data))
 
 	l2 := contents.lines[1]
-	ss2 := l2.sentences()
+	ss2 := l2.sentences(config.New())
 	assert.Len(t, ss2, 1)
 	assert.Equal(t, "Whose name was Jim", string(ss2[0].data))
 }
@@ -121,7 +123,7 @@ func Test_mdContents_sentences2(t *testing.T) {
 		data: []byte("**Sentence number one. And then, number two.*** And finally, three."),
 	}
 
-	sents := line.sentences()
+	sents := line.sentences(config.New())
 	assert.Len(t, sents, 5)
 	assert.Equal(t, "**Sentence number one", string(sents[0].data))
 	assert.Equal(t, "And then", string(sents[1].data))
@@ -129,3 +131,42 @@ func Test_mdContents_sentences2(t *testing.T) {
 	assert.Equal(t, "*** And finally", string(sents[3].data))
 	assert.Equal(t, "three", string(sents[4].data))
 }
+
+func Test_ProcessMD_Cancel(t *testing.T) {
+	doc := strings.Repeat(mdMediumText, 50000)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	out := ProcessMD(cancelled, config.New(), &inputReadCloser{strings.NewReader(doc)})
+	assert.ErrorIs(t, out.Err, context.Canceled)
+
+	timed, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	out = ProcessMD(timed, config.New(), &inputReadCloser{strings.NewReader(doc)})
+	assert.ErrorIs(t, out.Err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func Test_ProcessMD_PositionWeights(t *testing.T) {
+	const doc = `
+Subscribe
+
+# Headline
+
+Elections are coming.
+Elections matter.
+
+Weather is fine.
+`
+
+	cfg := config.New(config.NoStopWords(true), config.PositionWeights(config.StepDecay(1, 2)))
+	out := ProcessMD(context.Background(), cfg, &inputReadCloser{strings.NewReader(doc)})
+	assert.Nil(t, out.Err)
+
+	// first sentence & lines of the lede after the h1 are boosted
+	p := cfg.TagWeights[paragraph.String()]
+	assert.Equal(t, 2*p, out.RawTags["subscribe"].Score)
+	assert.Equal(t, 4*p, out.RawTags["elections"].Score)
+	assert.Equal(t, p, out.RawTags["weather"].Score)
+}
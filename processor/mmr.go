@@ -0,0 +1,183 @@
+package processor
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zoomio/tagify/model"
+)
+
+// mmrPool is the number of candidates per requested tag,
+// which are considered by the diversification.
+const mmrPool = 10
+
+// diversify re-ranks given ranked tags with Maximal Marginal Relevance (Carbonell & Goldstein, 1998)
+// and returns up to limit of them: the next tag is the one with the best trade-off
+// between its relevance and its similarity to the already picked tags.
+//
+// relevance gives the relevance of the tag within the interval of 0.0 to 1.0,
+// lambda of 1.0 keeps the original order, lambda of 0.0 favours diversity only.
+func diversify(items []*model.Tag, limit int, lambda float64, relevance func(*model.Tag) float64) []*model.Tag {
+	if limit <= 0 || len(items) == 0 {
+		return []*model.Tag{}
+	}
+
+	pool := len(items)
+	if limit*mmrPool < pool {
+		pool = limit * mmrPool
+	}
+	candidates := make([]*model.Tag, pool)
+	copy(candidates, items[:pool])
+
+	rel := make([]float64, pool)
+	for i, t := range candidates {
+		rel[i] = relevance(t)
+	}
+
+	// the highest similarity of each candidate to the picked tags,
+	// updated with the last picked tag only
+	maxSim := make([]float64, pool)
+	picked := make([]bool, pool)
+
+	result := make([]*model.Tag, 0, limit)
+	for len(result) < limit && len(result) < pool {
+		best, bestScore := -1, math.Inf(-1)
+		for i := range candidates {
+			if picked[i] {
+				continue
+			}
+			score := lambda*rel[i] - (1-lambda)*maxSim[i]
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		picked[best] = true
+		last := candidates[best]
+		result = append(result, last)
+
+		for i, t := range candidates {
+			if picked[i] {
+				continue
+			}
+			if s := tagSimilarity(t, last); s > maxSim[i] {
+				maxSim[i] = s
+			}
+		}
+	}
+
+	return result
+}
+
+// tagSimilarity tells how redundant the tags are to each other,
+// as the highest of their co-occurrence and string similarities.
+func tagSimilarity(a, b *model.Tag) float64 {
+	return math.Max(cooccurrence(a.Sentences, b.Sentences), stringSimilarity(a.Value, b.Value))
+}
+
+// cooccurrence is the cosine similarity of the sets of (sorted) sentences,
+// in which the tags appeared.
+func cooccurrence(a, b []int) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	var common int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			common++
+			i++
+			j++
+		}
+	}
+	return float64(common) / math.Sqrt(float64(len(a))*float64(len(b)))
+}
+
+// stringSimilarity is the highest of the normalized Levenshtein similarity
+// and the Jaccard similarity of the words of the given values.
+func stringSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	var lev float64
+	if n := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b)); n > 0 {
+		lev = 1 - float64(levenshtein([]rune(a), []rune(b)))/float64(n)
+	}
+	return math.Max(lev, wordsJaccard(a, b))
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func wordsJaccard(a, b string) float64 {
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	if len(wa) < 2 && len(wb) < 2 {
+		return 0
+	}
+	set := make(map[string]bool, len(wa))
+	for _, w := range wa {
+		set[w] = true
+	}
+	var common int
+	union := len(set)
+	seen := make(map[string]bool, len(wb))
+	for _, w := range wb {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		if set[w] {
+			common++
+		} else {
+			union++
+		}
+	}
+	return float64(common) / float64(union)
+}
+
+// mergeSentences merges given sorted sentences of the tags into the sorted set.
+func mergeSentences(a, b []int) []int {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	res := make([]int, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			res = append(res, a[i])
+			i++
+		case a[i] > b[j]:
+			res = append(res, b[j])
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+	res = append(res, a[i:]...)
+	return append(res, b[j:]...)
+}
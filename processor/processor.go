@@ -1,11 +1,13 @@
 package processor
 
 import (
+	"context"
 	"math"
 
 	"github.com/jinzhu/inflection"
 
 	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/extension"
 	"github.com/zoomio/tagify/model"
 	"github.com/zoomio/tagify/processor/util"
 )
@@ -15,12 +17,29 @@ import (
 // then sorts de-duped list again and
 // takes only requested size (limit) or just everything if result is smaller than limit.
 //
+// Keywords of RAKE & YAKE (see config.Algorithm) are only sorted by their native scores.
+//
+// With config.Config.Diversify the requested size is picked with Maximal Marginal Relevance,
+// which penalizes tags co-occurring with or looking like the better ranked ones.
+//
+// Context is passed to the pre & post ranking extensions (see ExtPreRank & ExtPostRank).
+//
 // nolint: gocyclo
-func Run(c *config.Config, items []*model.Tag) []*model.Tag {
+func Run(ctx context.Context, c *config.Config, items []*model.Tag) []*model.Tag {
+	// results of the extensions are collected per run, unless it has been started already
+	ctx = extension.WithRun(ctx)
+
+	if c.Algorithm == config.RAKE || c.Algorithm == config.YAKE {
+		return runKeywords(ctx, c, items)
+	}
+
 	uniqueTags := make([]*model.Tag, 0)
 	seenTagValues := make(map[string]int)
 	uniqueTagsMap := make(map[string]int)
 
+	// allow for extensions
+	items = extPreRank(ctx, c, items)
+
 	util.SortTagItems(items)
 
 	for i, tag := range items {
@@ -65,6 +84,8 @@ func Run(c *config.Config, items []*model.Tag) []*model.Tag {
 				Count:     saved.Count + tag.Count,
 				Docs:      saved.Docs + tag.Docs,
 				DocsCount: saved.DocsCount,
+				Sentences: mergeSentences(saved.Sentences, tag.Sentences),
+				Entity:    saved.Entity,
 			}
 		}
 	}
@@ -78,18 +99,81 @@ func Run(c *config.Config, items []*model.Tag) []*model.Tag {
 
 	util.SortTagItems(uniqueTags)
 
-	// take only requested size (limit) or just everything if result is smaller than limit
-	resLen := int(math.Min(float64(c.Limit), float64(len(uniqueTags))))
-	result := make([]*model.Tag, resLen)
-	copy(result, uniqueTags[:resLen])
+	var result []*model.Tag
+	if c.Diversify && len(uniqueTags) > 0 {
+		best := uniqueTags[0].Score
+		result = diversify(uniqueTags, c.Limit, c.MMRLambda, func(t *model.Tag) float64 {
+			if best <= 0 {
+				return 0
+			}
+			return t.Score / best
+		})
+	} else {
+		// take only requested size (limit) or just everything if result is smaller than limit
+		resLen := int(math.Min(float64(c.Limit), float64(len(uniqueTags))))
+		result = make([]*model.Tag, resLen)
+		copy(result, uniqueTags[:resLen])
+	}
 
 	// adjust scores to the interval of 0.0 to 1.0
-	if c.AdjustScores {
+	if c.AdjustScores && len(result) > 0 {
 		maxScore := result[0].Score
 		for _, t := range result {
 			t.Score = t.Score / maxScore
 		}
 	}
 
-	return result
+	// allow for extensions
+	return extPostRank(ctx, c, result)
+}
+
+// runKeywords ranks keywords by their native scores,
+// which are better when higher for RAKE and when lower for YAKE.
+func runKeywords(ctx context.Context, c *config.Config, items []*model.Tag) []*model.Tag {
+	// allow for extensions
+	items = extPreRank(ctx, c, items)
+
+	if c.Algorithm == config.YAKE {
+		util.SortTagItemsAsc(items)
+	} else {
+		util.SortTagItems(items)
+	}
+
+	var result []*model.Tag
+	if c.Diversify && len(items) > 0 {
+		best := items[0].Score
+		result = diversify(items, c.Limit, c.MMRLambda, func(t *model.Tag) float64 {
+			if c.Algorithm == config.YAKE {
+				if t.Score <= 0 {
+					return 1
+				}
+				return math.Min(best/t.Score, 1)
+			}
+			if best <= 0 {
+				return 0
+			}
+			return t.Score / best
+		})
+	} else {
+		resLen := int(math.Min(float64(c.Limit), float64(len(items))))
+		result = make([]*model.Tag, resLen)
+		copy(result, items[:resLen])
+	}
+
+	// adjust scores to the interval of 0.0 to 1.0, where 1.0 is the best
+	if c.AdjustScores && len(result) > 0 {
+		best := result[0].Score
+		for _, t := range result {
+			if c.Algorithm == config.YAKE {
+				if t.Score > 0 {
+					t.Score = best / t.Score
+				}
+			} else {
+				t.Score = t.Score / best
+			}
+		}
+	}
+
+	// allow for extensions
+	return extPostRank(ctx, c, result)
 }
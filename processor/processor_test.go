@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,7 +19,7 @@ func Test_Run_Limits(t *testing.T) {
 		{Value: "bee", Score: 1},
 	}
 	c := config.New(config.Limit(3), config.AdjustScores(false))
-	processed := Run(c, items)
+	processed := Run(context.TODO(), c, items)
 	assert.Len(t, processed, 3)
 }
 
@@ -31,7 +32,7 @@ func Test_Run_Sorts(t *testing.T) {
 		{Value: "bee", Score: 4},
 	}
 	c := config.New(config.Limit(5), config.AdjustScores(false))
-	processed := Run(c, items)
+	processed := Run(context.TODO(), c, items)
 	assert.Len(t, processed, 5)
 	assert.Equal(t, "foo", processed[0].Value)
 	assert.Equal(t, "bee", processed[1].Value)
@@ -49,7 +50,7 @@ func Test_Run_DeDupes(t *testing.T) {
 		{Value: "cats", Score: 1},
 	}
 	c := config.New(config.Limit(5), config.AdjustScores(false))
-	processed := Run(c, items)
+	processed := Run(context.TODO(), c, items)
 	assert.Len(t, processed, 3)
 	assert.Equal(t, "people", processed[0].Value)
 	assert.Equal(t, 7.0, processed[0].Score)
@@ -64,7 +65,7 @@ func Test_Run_IgnoresTFIDF_IfNoDocs(t *testing.T) {
 		{Value: "cat", Score: 5},
 	}
 	c := config.New(config.Limit(5), config.AdjustScores(false))
-	processed := Run(c, items)
+	processed := Run(context.TODO(), c, items)
 	assert.Equal(t, 5.0, processed[0].Score)
 }
 
@@ -73,7 +74,7 @@ func Test_Run_AppliesTFIDF_WithDocs(t *testing.T) {
 		{Value: "cat", Score: 5, Docs: 1, DocsCount: 3},
 	}
 	c := config.New(config.Limit(5), config.AdjustScores(false))
-	processed := Run(c, items)
+	processed := Run(context.TODO(), c, items)
 	assert.Equal(t, 1.9684489712313906, processed[0].Score)
 }
 
@@ -86,7 +87,7 @@ func Test_Run_AdjustsScores(t *testing.T) {
 		{Value: "cats", Score: 1},
 	}
 	c := config.New(config.Limit(5), config.AdjustScores(true))
-	processed := Run(c, items)
+	processed := Run(context.TODO(), c, items)
 	assert.Len(t, processed, 3)
 	assert.Equal(t, "people", processed[0].Value)
 	assert.Equal(t, 1.0, processed[0].Score)
@@ -95,3 +96,51 @@ func Test_Run_AdjustsScores(t *testing.T) {
 	assert.Equal(t, "bar", processed[2].Value)
 	assert.Equal(t, 0.42857142857142855, processed[2].Score)
 }
+
+func Test_Run_Diversify(t *testing.T) {
+	items := []*model.Tag{
+		{Value: "data", Score: 10, Sentences: []int{1, 2, 3}},
+		{Value: "dataset", Score: 9, Sentences: []int{4}},
+		{Value: "kernel", Score: 8, Sentences: []int{5, 6}},
+		{Value: "python", Score: 7, Sentences: []int{1, 2, 3}},
+		{Value: "cloud", Score: 5, Sentences: []int{7}},
+	}
+
+	tests := []struct {
+		name   string
+		lambda float64
+		want   []string
+	}{
+		{"relevance only", 1, []string{"data", "dataset", "kernel"}},
+		{"balanced", 0.5, []string{"data", "kernel", "cloud"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := make([]*model.Tag, len(items))
+			for i, v := range items {
+				cp := *v
+				in[i] = &cp
+			}
+			c := config.New(config.Limit(3), config.AdjustScores(false), config.Diversify(tt.lambda))
+			processed := Run(context.TODO(), c, in)
+			values := make([]string, len(processed))
+			for i, v := range processed {
+				values[i] = v.Value
+			}
+			assert.Equal(t, tt.want, values)
+		})
+	}
+}
+
+func Test_stringSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, stringSimilarity("data", "data"))
+	assert.InDelta(t, 0.571, stringSimilarity("data", "dataset"), 0.001)
+	assert.Equal(t, 0.5, stringSimilarity("machine learning", "learning"))
+	assert.Equal(t, 0.0, stringSimilarity("abc", "xyz"))
+}
+
+func Test_mergeSentences(t *testing.T) {
+	assert.Equal(t, []int{1, 2, 3, 5, 8}, mergeSentences([]int{1, 3, 8}, []int{2, 3, 5}))
+	assert.Nil(t, mergeSentences(nil, nil))
+}
@@ -2,18 +2,22 @@ package text
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha512"
 	"fmt"
+	"hash"
 	"io"
 	"strings"
 
 	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/dedup"
 	"github.com/zoomio/tagify/model"
+	"github.com/zoomio/tagify/processor/keywords"
 	"github.com/zoomio/tagify/processor/util"
 )
 
 // ProcessText parses given text lines of text into a slice of tags.
-var ProcessText model.ProcessFunc = func(c *config.Config, in io.ReadCloser) *model.Result {
+var ProcessText model.ProcessFunc = func(ctx context.Context, c *config.Config, in io.ReadCloser) *model.Result {
 
 	if c.Verbose {
 		fmt.Println("parsing plain text...")
@@ -23,11 +27,15 @@ var ProcessText model.ProcessFunc = func(c *config.Config, in io.ReadCloser) *mo
 	// 	fmt.Printf("using configuration: %#v\n", c)
 	// }
 
-	var docsCount int
+	if c.Stream {
+		return processStream(ctx, c, in)
+	}
 
 	defer in.Close()
 	buf := new(bytes.Buffer)
-	_, _ = buf.ReadFrom(in)
+	if _, err := buf.ReadFrom(in); err != nil {
+		return model.ErrResult(err)
+	}
 	inStr := buf.String()
 	lines := strings.FieldsFunc(inStr, func(r rune) bool {
 		return r == '\n'
@@ -41,54 +49,149 @@ var ProcessText model.ProcessFunc = func(c *config.Config, in io.ReadCloser) *mo
 		return &model.Result{}
 	}
 
-	tokenIndex := make(map[string]*model.Tag)
-	tokens := make([]string, 0)
+	ix := newTextIndex(c, false)
 	for _, l := range lines {
-		// detect language and setup stop words for it
-		if !c.SkipLang && c.StopWords == nil && len(l) > 0 {
-			config.DetectLang(c, l)
+		if err := ix.addLine(ctx, []byte(l)); err != nil {
+			return model.ErrResult(err)
+		}
+	}
+	return ix.result()
+}
+
+// processStream goes through the input line by line, only the current line
+// and the index of the distinct tokens are kept in memory.
+func processStream(ctx context.Context, c *config.Config, in io.ReadCloser) *model.Result {
+	defer in.Close()
+
+	if c.Verbose && (c.Algorithm != config.Frequency || c.Diversify) {
+		fmt.Println("keywords algorithms & co-occurrence of the tags for the diversification aren't used in the stream mode")
+	}
+
+	var linesCount int
+	ix := newTextIndex(c, true)
+	scanner := util.NewLineScanner(in)
+	for scanner.Scan() {
+		l := scanner.Bytes()
+		if len(l) == 0 {
+			continue
+		}
+		linesCount++
+		if err := ix.addLine(ctx, l); err != nil {
+			return model.ErrResult(err)
 		}
-		sentences := util.SplitToSentences([]byte(l))
-		for _, s := range sentences {
-			docsCount++
-			tokens = append(tokens, util.SplitToTokens(s, c)...)
-			visited := map[string]bool{}
-			for _, token := range tokens {
-				visited[token] = true
-				item, ok := tokenIndex[token]
-				if !ok {
-					item = &model.Tag{Value: token}
-					tokenIndex[token] = item
-				}
-				item.Score++
-				item.Count++
+	}
+	if err := scanner.Err(); err != nil {
+		return model.ErrResult(err)
+	}
+
+	if c.Verbose {
+		fmt.Printf("got %d lines\n", linesCount)
+	}
+
+	if linesCount == 0 {
+		return &model.Result{}
+	}
+	return ix.result()
+}
+
+// textIndex collects tags of the lines of the plain text.
+// In the stream mode, keywords algorithms (RAKE & YAKE) aren't applied and sentences of the tags aren't tracked
+// for the diversification, since they keep state per occurrence of the token,
+// hence memory is bounded by the distinct tokens.
+type textIndex struct {
+	cfg       *config.Config
+	stream    bool
+	tags      map[string]*model.Tag
+	docsCount int
+	hash      hash.Hash
+
+	sig      *dedup.Signer
+	kw       keywords.Extractor
+	pos      *util.Position
+	sections *util.Sections
+}
+
+func newTextIndex(c *config.Config, stream bool) *textIndex {
+	ix := &textIndex{
+		cfg:      c,
+		stream:   stream,
+		tags:     make(map[string]*model.Tag),
+		hash:     sha512.New(),
+		sig:      util.NewSigner(c),
+		pos:      util.NewPosition(c),
+		sections: util.NewSections(c),
+	}
+	if !stream {
+		ix.kw = keywords.New(c)
+	}
+	return ix
+}
+
+// addLine adds tokens of the sentences of the given line, error is returned once context is done.
+func (ix *textIndex) addLine(ctx context.Context, l []byte) error {
+	c := ix.cfg
+	// detect language and setup stop words for it
+	if !c.SkipLang && c.StopWords == nil && len(l) > 0 {
+		config.DetectLang(c, string(l))
+	}
+	lc := ix.sections.Config(l, c)
+
+	for _, s := range util.SplitToSentencesWith(l, c) {
+		if util.Done(ctx) {
+			return ctx.Err()
+		}
+
+		ix.docsCount++
+		if ix.kw != nil {
+			ix.kw.Add(s)
+		}
+		weight := ix.pos.Next(false)
+		sntTokens, entities := util.SplitToTokensWithEntities(s, true, lc)
+		ix.sig.Add(sntTokens...)
+		visited := map[string]bool{}
+		weights := util.TokenWeights(sntTokens, lc)
+		for k, token := range sntTokens {
+			_, _ = ix.hash.Write([]byte(token))
+			visited[token] = true
+			item, ok := ix.tags[token]
+			if !ok {
+				item = &model.Tag{Value: token, Entity: entities[token]}
+				ix.tags[token] = item
 			}
-			// increment number of appearances in documents for each visited tag
-			for token := range visited {
-				tokenIndex[token].Docs++
+			item.Score += weight * weights.At(k)
+			item.Count++
+		}
+		// increment number of appearances in documents for each visited tag
+		for token := range visited {
+			ix.tags[token].Docs++
+			if c.Diversify && !ix.stream {
+				ix.tags[token].Sentences = append(ix.tags[token].Sentences, ix.docsCount)
 			}
 		}
 	}
+	return nil
+}
 
-	// set total number of dicuments in the text.
+func (ix *textIndex) result() *model.Result {
+	// set total number of documents in the text.
+	tokenIndex := ix.tags
 	for _, v := range tokenIndex {
-		v.DocsCount = docsCount
+		v.DocsCount = ix.docsCount
+	}
+	if ix.kw != nil {
+		tokenIndex = ix.kw.Tags()
 	}
 
-	return &model.Result{
-		RawTags: tokenIndex,
-		Meta: &model.Meta{
-			ContentType: config.Text,
-			DocHash:     fmt.Sprintf("%x", hashTokens(tokens)),
-			Lang:        c.Lang,
-		},
+	meta := &model.Meta{
+		ContentType: config.Text,
+		DocHash:     fmt.Sprintf("%x", ix.hash.Sum(nil)),
+		Lang:        ix.cfg.Lang,
 	}
-}
+	ix.sig.Sign(meta)
+	ix.sections.Meta(meta)
 
-func hashTokens(ts []string) []byte {
-	h := sha512.New()
-	for _, t := range ts {
-		_, _ = h.Write([]byte(t))
+	return &model.Result{
+		RawTags: tokenIndex,
+		Meta:    meta,
 	}
-	return h.Sum(nil)
 }
@@ -1,7 +1,10 @@
 package text
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/zoomio/inout"
@@ -22,32 +25,91 @@ var (
 )
 
 func Test_ParseText_Empty(t *testing.T) {
-	out := ProcessText(config.New(), inout.NewFromString(""))
+	out := ProcessText(context.Background(), config.New(), inout.NewFromString(""))
 	assert.Len(t, out.RawTags, 0)
 }
 
 func Test_ParseText_WithStopWords(t *testing.T) {
-	out := ProcessText(config.New(), inout.NewFromString(text))
+	out := ProcessText(context.Background(), config.New(), inout.NewFromString(text))
 	assert.Len(t, out.RawTags, 7)
 	assert.Subset(t, model.ToStrings(out.Flatten()), []string{"there", "was", "a", "boy", "who's", "name", "jim"})
 }
 
 func Test_ParseText_NoStopWords(t *testing.T) {
-	out := ProcessText(config.New(config.NoStopWords(true)), inout.NewFromString(text))
+	out := ProcessText(context.Background(), config.New(config.NoStopWords(true)), inout.NewFromString(text))
 	assert.Len(t, out.RawTags, 2)
 	assert.Subset(t, model.ToStrings(out.Flatten()), []string{"boy", "jim"})
 }
 
 func Test_calculatesVersion(t *testing.T) {
-	out1 := ProcessText(config.New(), inout.NewFromString(text))
+	out1 := ProcessText(context.Background(), config.New(), inout.NewFromString(text))
 	assert.Nil(t, out1.Err)
 	assert.Equal(t,
 		"323c7bf1fe804151d8c378648061d861554a4ae5d02558ce140c1ee3ff186c37a1600bab87abada56d50148b35c121c8b1abb5db8c13a75e9d676fd9130f3c6a",
 		out1.Meta.DocHash)
 
-	out2 := ProcessText(config.New(), inout.NewFromString(text2))
+	out2 := ProcessText(context.Background(), config.New(), inout.NewFromString(text2))
 	assert.Nil(t, out2.Err)
 	assert.Equal(t,
 		"2f1ba6d722f14042db22ea7c433d02c8b666b33106b1c18bac00388b0ee3add19f411b234981293864698fc9e9dc9073b966378bcb6f49b1c7f07ca99a17a5cc",
 		out2.Meta.DocHash)
 }
+
+func Test_ProcessText_Cancel(t *testing.T) {
+	doc := strings.Repeat("There was a boy whose name was Jim. His friends were very good to him.\n", 100000)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	out := ProcessText(cancelled, config.New(), inout.NewFromString(doc))
+	assert.ErrorIs(t, out.Err, context.Canceled)
+
+	timed, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	out = ProcessText(timed, config.New(), inout.NewFromString(doc))
+	assert.ErrorIs(t, out.Err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func Test_ProcessText_Stream(t *testing.T) {
+	out := ProcessText(context.Background(), config.New(config.Stream(true)), inout.NewFromString(""))
+	assert.Len(t, out.RawTags, 0)
+
+	out = ProcessText(context.Background(), config.New(config.Stream(true), config.NoStopWords(true)), inout.NewFromString(text))
+	assert.Nil(t, out.Err)
+	assert.ElementsMatch(t, []string{"boy", "jim"}, model.ToStrings(out.Flatten()))
+
+	// same document gives same hash in both modes
+	expected := ProcessText(context.Background(), config.New(), inout.NewFromString(text))
+	out = ProcessText(context.Background(), config.New(config.Stream(true)), inout.NewFromString(text))
+	assert.Equal(t, expected.Meta.DocHash, out.Meta.DocHash)
+	assert.Equal(t, "en", out.Meta.Lang)
+
+	// every sentence is counted once
+	doc := strings.Repeat("There was a boy whose name was Jim.\n", 1000)
+	out = ProcessText(context.Background(), config.New(config.Stream(true), config.NoStopWords(true)), inout.NewFromString(doc))
+	assert.Nil(t, out.Err)
+	assert.Equal(t, 1000, out.RawTags["jim"].Count)
+	assert.Equal(t, 1000, out.RawTags["jim"].Docs)
+	assert.Equal(t, 1000, out.RawTags["jim"].DocsCount)
+
+	// state per occurrence of the tokens isn't kept
+	out = ProcessText(context.Background(), config.New(config.Stream(true), config.NoStopWords(true),
+		config.KeywordAlgorithm(config.RAKE), config.Diversify(0.5)), inout.NewFromString(doc))
+	assert.Nil(t, out.Err)
+	assert.Equal(t, 1000, out.RawTags["jim"].Count)
+	assert.Empty(t, out.RawTags["jim"].Sentences)
+}
+
+func Test_ProcessText_PositionWeights(t *testing.T) {
+	doc := "Elections are coming. Weather is fine. Weather is fine. Elections again."
+
+	for _, stream := range []bool{false, true} {
+		cfg := config.New(config.NoStopWords(true), config.Stream(stream), config.PositionWeights(config.StepDecay(1, 3)))
+		out := ProcessText(context.Background(), cfg, inout.NewFromString(doc))
+		assert.Nil(t, out.Err)
+		assert.Equal(t, 4.0, out.RawTags["elections"].Score)
+		assert.Equal(t, 2.0, out.RawTags["weather"].Score)
+		assert.Equal(t, 2, out.RawTags["elections"].Count)
+	}
+}
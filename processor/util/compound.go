@@ -0,0 +1,64 @@
+package util
+
+import (
+	"github.com/zoomio/tagify/config"
+)
+
+// decompound appends parts of the compounds (see config.Decompound) right after them.
+func decompound(tokens []string, d *config.Decompounder) []string {
+	if d == nil {
+		return tokens
+	}
+	result := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, token)
+		result = append(result, d.Split(token)...)
+	}
+	return result
+}
+
+// Weights are multipliers of the weights of the tokens, nil weights are all 1.
+type Weights []float64
+
+// At returns multiplier of the weight of the token at the given index.
+func (w Weights) At(i int) float64 {
+	if w == nil {
+		return 1
+	}
+	return w[i]
+}
+
+// TokenWeights returns multipliers of the weights of the given tokens (see SplitToTokens),
+// which are config.Config.CompoundPartWeight for the parts of the compounds and 1 for the rest,
+// nil is returned if compounds aren't split.
+func TokenWeights(tokens []string, cfg *config.Config) Weights {
+	d := cfg.Decompounder()
+	if d == nil {
+		return nil
+	}
+	weights := make(Weights, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		weights[i] = 1
+		parts := d.Split(tokens[i])
+		if len(parts) == 0 || !hasPrefix(tokens[i+1:], parts) {
+			continue
+		}
+		for k := range parts {
+			weights[i+1+k] = cfg.CompoundPartWeight
+		}
+		i += len(parts)
+	}
+	return weights
+}
+
+func hasPrefix(tokens, prefix []string) bool {
+	if len(tokens) < len(prefix) {
+		return false
+	}
+	for i, v := range prefix {
+		if tokens[i] != v {
+			return false
+		}
+	}
+	return true
+}
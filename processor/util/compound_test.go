@@ -0,0 +1,22 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/config"
+)
+
+func Test_SplitToTokens_Decompound(t *testing.T) {
+	cfg := config.New(config.Language("de"), config.Decompound(0.5))
+	tokens := SplitToTokens([]byte("Die Datenschutzgrundverordnung gilt"), cfg)
+	assert.Equal(t, []string{"die", "datenschutzgrundverordnung", "datenschutz", "grund", "verordnung", "gilt"}, tokens)
+	assert.Equal(t, Weights{1, 1, 0.5, 0.5, 0.5, 1}, TokenWeights(tokens, cfg))
+
+	cfg = config.New(config.Language("de"))
+	tokens = SplitToTokens([]byte("Die Datenschutzgrundverordnung gilt"), cfg)
+	assert.Equal(t, []string{"die", "datenschutzgrundverordnung", "gilt"}, tokens)
+	assert.Nil(t, TokenWeights(tokens, cfg))
+	assert.Equal(t, 1.0, TokenWeights(tokens, cfg).At(1))
+}
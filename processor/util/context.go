@@ -0,0 +1,13 @@
+package util
+
+import "context"
+
+// Done tells whether the context is done, it is cheap enough to be checked inside of loops.
+func Done(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,198 @@
+package util
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/zoomio/stopwords"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/model"
+)
+
+// MaxTitleWords is the maximum number of words in the quoted titles.
+const MaxTitleWords = 8
+
+var (
+	quotedRegex = regexp.MustCompile(`"([^"]+)"|“([^”]+)”|«([^»]+)»`)
+	wordRegex   = regexp.MustCompile(`[\p{L}\p{N}][\p{L}\p{N}&'’.-]*`)
+
+	// lowercase words allowed inside of the names, e.g. "Bank of America"
+	nameConnectors = map[string]bool{
+		"of": true, "de": true, "da": true, "del": true, "van": true, "von": true, "der": true, "la": true, "le": true,
+	}
+)
+
+// Entity is a named entity found in the text before it is lowercased.
+type Entity struct {
+	Value string
+	Kind  model.EntityKind
+}
+
+type entityWord struct {
+	value      string
+	start, end int
+	capital    bool // starts with the capital letter
+	acronym    bool // has only capital letters
+}
+
+// FindEntities returns quoted titles, capitalized sequences of words & acronyms of the given text
+// along with the text, in which they are blanked out.
+// Initial tells whether the text starts a sentence, then its 1st word is only taken if it is an acronym
+// or if it isn't a stop word and is capitalized elsewhere in the text.
+func FindEntities(text []byte, initial bool, reg *stopwords.Register) ([]Entity, []byte) {
+	rest := append([]byte(nil), text...)
+	entities := make([]Entity, 0)
+
+	// quoted titles
+	for _, m := range quotedRegex.FindAllSubmatchIndex(rest, -1) {
+		for g := 2; g < len(m); g += 2 {
+			if m[g] < 0 {
+				continue
+			}
+			value := strings.TrimRight(strings.TrimSpace(string(rest[m[g]:m[g+1]])), ".,!?;:")
+			r, _ := utf8.DecodeRuneInString(value)
+			if !unicode.IsUpper(r) || len(strings.Fields(value)) > MaxTitleWords {
+				continue
+			}
+			entities = append(entities, Entity{Value: value, Kind: model.Title})
+			blank(rest, m[0], m[1])
+		}
+	}
+
+	// all caps text (e.g. headlines) has no distinguishable names
+	if !bytes.ContainsFunc(rest, unicode.IsLower) {
+		return entities, rest
+	}
+
+	words := entityWords(rest)
+	// capital letter of the 1st word is ambiguous, since every sentence starts with it,
+	// hence it is only taken if it isn't a stop word and is capitalized elsewhere in the text
+	if initial && len(words) > 0 && !words[0].acronym &&
+		((reg != nil && reg.IsStopWord(strings.ToLower(words[0].value))) || !capitalizedElsewhere(words, 0)) {
+		words[0].capital = false
+	}
+	for i := 0; i < len(words); {
+		if !words[i].capital {
+			i++
+			continue
+		}
+		// collect capitalized words, which are only separated by spaces,
+		// connectors are allowed in between of the capitalized words
+		j := i + 1
+		for j < len(words) && isSpace(rest[words[j-1].end:words[j].start]) {
+			if words[j].capital {
+				j++
+				continue
+			}
+			if nameConnectors[words[j].value] && j+1 < len(words) && words[j+1].capital &&
+				isSpace(rest[words[j].end:words[j+1].start]) {
+				j += 2
+				continue
+			}
+			break
+		}
+		seq := words[i:j]
+		i = j
+
+		// leading stop word is not part of the name, e.g. "The" in "The New York Times"
+		if reg != nil && reg.IsStopWord(strings.ToLower(seq[0].value)) {
+			seq = seq[1:]
+		}
+		if len(seq) == 0 {
+			continue
+		}
+
+		kind := model.Name
+		if len(seq) == 1 && seq[0].acronym {
+			kind = model.Acronym
+		}
+		values := make([]string, len(seq))
+		for k, w := range seq {
+			values[k] = w.value
+		}
+		entities = append(entities, Entity{Value: strings.Join(values, " "), Kind: kind})
+		blank(rest, seq[0].start, seq[len(seq)-1].end)
+	}
+
+	return entities, rest
+}
+
+// SplitToTokensWithEntities splits given text into tokens, which include named entities with
+// the original casing if they are enabled (see config.Entities), entities are returned along with their kinds.
+// Initial tells whether the text starts a sentence.
+func SplitToTokensWithEntities(text []byte, initial bool, cfg *config.Config) ([]string, map[string]model.EntityKind) {
+	if !cfg.Entities {
+		return SplitToTokens(text, cfg), nil
+	}
+	text = NormalizeText(text, cfg)
+	// preserved tokens aren't entities, e.g. "NET" of ".NET"
+	masked, spans := maskPreserved(text, cfg, '|')
+	entities, rest := FindEntities(masked, initial, cfg.LangStopWords())
+	for _, s := range spans {
+		copy(rest[s.start:s.end], text[s.start:s.end])
+	}
+	tokens := splitToTokens(rest, cfg)
+	if len(entities) == 0 {
+		return tokens, nil
+	}
+	kinds := make(map[string]model.EntityKind, len(entities))
+	for _, e := range entities {
+		tokens = append(tokens, e.Value)
+		kinds[e.Value] = e.Kind
+	}
+	return tokens, kinds
+}
+
+func entityWords(text []byte) []*entityWord {
+	locs := wordRegex.FindAllIndex(text, -1)
+	words := make([]*entityWord, 0, len(locs))
+	for _, loc := range locs {
+		value := string(text[loc[0]:loc[1]])
+		// possessives & trailing dots aren't part of the names
+		for _, suffix := range []string{"'s", "’s", "'", "’"} {
+			value = strings.TrimSuffix(value, suffix)
+		}
+		value = strings.TrimRight(value, ".-")
+		w := &entityWord{value: value, start: loc[0], end: loc[0] + len(value)}
+
+		var letters, upper int
+		for _, r := range value {
+			if unicode.IsLetter(r) {
+				letters++
+				if unicode.IsUpper(r) {
+					upper++
+				}
+			}
+		}
+		first, _ := utf8.DecodeRuneInString(value)
+		// single letters (e.g. "I" or "A") are too ambiguous
+		w.capital = unicode.IsUpper(first) && letters > 1
+		w.acronym = w.capital && upper == letters
+		words = append(words, w)
+	}
+	return words
+}
+
+// capitalizedElsewhere tells whether the word of the given index is found capitalized at any other index.
+func capitalizedElsewhere(words []*entityWord, idx int) bool {
+	for i, w := range words {
+		if i != idx && w.capital && w.value == words[idx].value {
+			return true
+		}
+	}
+	return false
+}
+
+func isSpace(bs []byte) bool {
+	return len(bytes.TrimSpace(bs)) == 0
+}
+
+func blank(bs []byte, start, end int) {
+	for i := start; i < end; i++ {
+		bs[i] = ' '
+	}
+}
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/model"
+)
+
+func Test_FindEntities(t *testing.T) {
+	reg := config.New(config.Language("en")).LangStopWords()
+
+	tests := []struct {
+		name    string
+		in      string
+		initial bool
+		expect  []Entity
+	}{
+		{"names", "Yesterday Tim Cook visited Apple headquarters", true,
+			[]Entity{{"Tim Cook", model.Name}, {"Apple", model.Name}}},
+		{"initial word", "Apple sells apples", true, []Entity{}},
+		{"not initial", "Apple sells apples", false, []Entity{{"Apple", model.Name}}},
+		{"initial capitalized elsewhere", "Apple sells phones, Apple says", true,
+			[]Entity{{"Apple", model.Name}, {"Apple", model.Name}}},
+		{"initial stop word", "This Apple thing", true, []Entity{{"Apple", model.Name}}},
+		{"leading stop word", "He reads The New York Times", true, []Entity{{"New York Times", model.Name}}},
+		{"initial sequence", "New York Times reports", true, []Entity{{"York Times", model.Name}}},
+		{"connectors", "He works at Bank of America now", true, []Entity{{"Bank of America", model.Name}}},
+		{"acronyms", "NASA and the ESA launched it", true, []Entity{{"NASA", model.Acronym}, {"ESA", model.Acronym}}},
+		{"possessive", "we love Google's search", true, []Entity{{"Google", model.Name}}},
+		{"titles", `she read "War and Peace" and «Anna Karenina» twice`, true,
+			[]Entity{{"War and Peace", model.Title}, {"Anna Karenina", model.Title}}},
+		{"lowercase quote", `he said "maybe later" again`, true, []Entity{}},
+		{"all caps", "BREAKING NEWS FROM PARIS", true, []Entity{}},
+		{"pronoun", "then I left", true, []Entity{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entities, _ := FindEntities([]byte(tt.in), tt.initial, reg)
+			assert.Equal(t, tt.expect, entities)
+		})
+	}
+}
+
+func Test_SplitToTokensWithEntities(t *testing.T) {
+	cfg := config.New(config.Language("en"), config.NoStopWords(true), config.Entities(true))
+	tokens, entities := SplitToTokensWithEntities([]byte("Yesterday Tim Cook ate apples at Apple"), true, cfg)
+	assert.ElementsMatch(t, []string{"yesterday", "ate", "apples", "at", "Tim Cook", "Apple"}, tokens)
+	assert.Equal(t, map[string]model.EntityKind{"Tim Cook": model.Name, "Apple": model.Name}, entities)
+
+	tokens, entities = SplitToTokensWithEntities([]byte("Yesterday Tim Cook ate apples"), true, config.New(config.Language("en"), config.NoStopWords(true)))
+	assert.ElementsMatch(t, []string{"yesterday", "tim", "cook", "ate", "apples"}, tokens)
+	assert.Nil(t, entities)
+}
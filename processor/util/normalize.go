@@ -0,0 +1,58 @@
+package util
+
+import (
+	"bytes"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/zoomio/tagify/config"
+)
+
+var (
+	// combining diacritical marks of Latin, Greek & Cyrillic letters
+	accents = &unicode.RangeTable{R16: []unicode.Range16{{Lo: 0x0300, Hi: 0x036f, Stride: 1}}}
+)
+
+// NormalizeText applies NFKC normalization to the given text, which also folds full-width & half-width forms,
+// strips diacritics & tatweel of Arabic and points & cantillation marks of Hebrew, folds Cyrillic "ё" into "е",
+// accents are stripped as well for the languages with the accent folding (see config.FoldAccents).
+func NormalizeText(text []byte, cfg *config.Config) []byte {
+	if cfg.SkipNormalize {
+		return text
+	}
+	text = norm.NFKC.Bytes(text)
+	if bytes.IndexFunc(text, needsCleanup) >= 0 {
+		text = bytes.Map(cleanupRune, text)
+	}
+	if cfg.FoldsAccents() {
+		folded, _, err := transform.Bytes(transform.Chain(norm.NFD, runes.Remove(runes.In(accents)), norm.NFC), text)
+		if err == nil {
+			text = folded
+		}
+	}
+	return text
+}
+
+func needsCleanup(r rune) bool {
+	return cleanupRune(r) != r
+}
+
+// cleanupRune maps script-specific variants of the letters, -1 drops the rune.
+func cleanupRune(r rune) rune {
+	switch {
+	case r == 'ё':
+		return 'е'
+	case r == 'Ё':
+		return 'Е'
+	// tatweel, harakat & Quranic annotation marks of Arabic
+	case r == 0x0640, r >= 0x064b && r <= 0x065f, r == 0x0670, r >= 0x06d6 && r <= 0x06ed && unicode.Is(unicode.Mn, r):
+		return -1
+	// points & cantillation marks of Hebrew, punctuation (e.g. maqaf) is kept
+	case r >= 0x0591 && r <= 0x05c7 && unicode.Is(unicode.Mn, r):
+		return -1
+	}
+	return r
+}
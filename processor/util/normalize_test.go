@@ -0,0 +1,38 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/config"
+)
+
+func Test_NormalizeText(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []config.Option
+		in      string
+		expect  string
+	}{
+		{"full-width", nil, "Ｇｏｌａｎｇ　１２３", "Golang 123"},
+		{"half-width katakana", nil, "ｶﾀｶﾅ", "カタカナ"},
+		{"ligature", nil, "ﬁle", "file"},
+		{"arabic diacritics & tatweel", nil, "كِتَاب كـــتاب", "كتاب كتاب"},
+		{"hebrew points", nil, "שָׁלוֹם עַל־", "שלום על־"},
+		{"cyrillic yo", nil, "Ёлка ещё", "Елка еще"},
+		{"decomposed yo", nil, "ёж", "еж"},
+		{"accents are kept", []config.Option{config.Language("fr")}, "café", "café"},
+		{"accents", []config.Option{config.Language("fr"), config.FoldAccents("fr")}, "Café crème", "Cafe creme"},
+		{"accents of other language", []config.Option{config.Language("de"), config.FoldAccents("fr")}, "Müller", "Müller"},
+		{"accents of any language", []config.Option{config.Language("es"), config.FoldAccents("*")}, "año", "ano"},
+		{"skip", []config.Option{config.SkipNormalize(true)}, "Ｇｏ ещё", "Ｇｏ ещё"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.New(tt.options...)
+			assert.Equal(t, tt.expect, string(NormalizeText([]byte(tt.in), cfg)))
+		})
+	}
+}
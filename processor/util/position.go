@@ -0,0 +1,34 @@
+package util
+
+import (
+	"github.com/zoomio/tagify/config"
+)
+
+// Position gives multipliers of the weights by position of the sentences in the document
+// (see config.PositionWeights), nil Position is safe to use and always gives 1.0.
+type Position struct {
+	decay config.Decay
+	next  int
+}
+
+// NewPosition returns position of the sentences if positional weighing is enabled, otherwise nil.
+func NewPosition(cfg *config.Config) *Position {
+	if cfg.PositionDecay == nil {
+		return nil
+	}
+	return &Position{decay: cfg.PositionDecay}
+}
+
+// Next returns multiplier of the weights for the next sentence,
+// sentences of the lede are weighted as the first one.
+func (p *Position) Next(lede bool) float64 {
+	if p == nil {
+		return 1
+	}
+	pos := p.next
+	p.next++
+	if lede {
+		pos = 0
+	}
+	return p.decay(pos)
+}
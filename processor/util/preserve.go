@@ -0,0 +1,156 @@
+package util
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/zoomio/tagify/config"
+)
+
+var (
+	// the tokens are captured by the 1st group, since the preceding character is a part of the match
+	tokenRuleRegexes = map[config.TokenRule]*regexp.Regexp{
+		config.ProgLangs:   regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}_.])(asp\.net|\.net|c\+\+|c#|f#|objective-c|node\.js|vue\.js|next\.js)`),
+		config.Versions:    regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.])(\p{L}*\d+(?:\.\d+)+(?:-[\p{L}\p{N}]+)?)`),
+		config.Hashtags:    regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&#])(#[\p{L}\p{N}_]*\p{L}[\p{L}\p{N}_]*)`),
+		config.Mentions:    regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@])(@[\p{L}\p{N}_]+)`),
+		config.Identifiers: regexp.MustCompile(`(?:^|[^\p{L}\p{N}_])([\p{L}\p{N}]+(?:[-_][\p{L}\p{N}]+)*)`),
+	}
+
+	// rules claim tokens in this order, e.g. "Go1.22" is a version & not an identifier "Go1"
+	tokenRulesOrder = []config.TokenRule{config.ProgLangs, config.Versions, config.Hashtags, config.Mentions, config.Identifiers}
+)
+
+type span struct {
+	start, end int
+}
+
+// SplitToSentencesWith splits given text into slice of sentences,
+// punctuation of the preserved tokens (see config.PreserveTokens) doesn't end the sentences.
+func SplitToSentencesWith(text []byte, cfg *config.Config) [][]byte {
+	if !cfg.PreservesTokens() {
+		return SplitToSentences(text)
+	}
+	text = bytes.TrimSpace(text)
+	spans := preservedSpans(text, cfg)
+	split := append([]byte(nil), text...)
+	for i, j := 0, 0; i < len(split); i++ {
+		for j < len(spans) && spans[j].end <= i {
+			j++
+		}
+		if j < len(spans) && spans[j].start <= i {
+			continue
+		}
+		switch split[i] {
+		case '.', ',', '!', '?', ';', ':':
+			split[i] = '\n'
+		}
+	}
+	return sentencesOf(split)
+}
+
+// preservedSpans returns sorted & non-overlapping spans of the tokens to keep as is,
+// custom patterns go first.
+func preservedSpans(text []byte, cfg *config.Config) []span {
+	var spans []span
+	claim := func(s span) {
+		if s.start >= s.end {
+			return
+		}
+		for _, v := range spans {
+			if s.start < v.end && v.start < s.end {
+				return
+			}
+		}
+		spans = append(spans, s)
+	}
+
+	for _, re := range cfg.TokenPatterns {
+		for _, m := range re.FindAllSubmatchIndex(text, -1) {
+			if len(m) > 2 && m[2] >= 0 {
+				claim(span{m[2], m[3]})
+			} else {
+				claim(span{m[0], m[1]})
+			}
+		}
+	}
+
+	for _, rule := range tokenRulesOrder {
+		if !hasRule(cfg.TokenRules, rule) {
+			continue
+		}
+		for _, m := range tokenRuleRegexes[rule].FindAllSubmatchIndex(text, -1) {
+			s := span{m[2], m[3]}
+			if !endsToken(text, s.end, rule) {
+				continue
+			}
+			if rule == config.Identifiers && !isMixed(text[s.start:s.end]) {
+				continue
+			}
+			claim(s)
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+	return spans
+}
+
+func hasRule(rules []config.TokenRule, rule config.TokenRule) bool {
+	for _, r := range rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// endsToken tells whether the token ends at the given position of the text.
+func endsToken(text []byte, end int, rule config.TokenRule) bool {
+	if end >= len(text) {
+		return true
+	}
+	r, _ := utf8.DecodeRune(text[end:])
+	if rule == config.ProgLangs && (r == '+' || r == '#') {
+		return false
+	}
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+}
+
+// isMixed tells whether the token has both letters & digits.
+func isMixed(token []byte) bool {
+	return bytes.IndexFunc(token, unicode.IsLetter) >= 0 && bytes.IndexFunc(token, unicode.IsDigit) >= 0
+}
+
+// splitPreserving splits given text into tokens, preserved tokens are only lowercased.
+func splitPreserving(text []byte, cfg *config.Config, split func([]byte) []string) []string {
+	tokens := make([]string, 0)
+	var pos int
+	for _, s := range preservedSpans(text, cfg) {
+		tokens = append(tokens, split(text[pos:s.start])...)
+		tokens = append(tokens, strings.ToLower(string(text[s.start:s.end])))
+		pos = s.end
+	}
+	return append(tokens, split(text[pos:])...)
+}
+
+// maskPreserved fills preserved tokens of the given text with the mask,
+// returns masked copy of the text & spans of the preserved tokens.
+func maskPreserved(text []byte, cfg *config.Config, mask byte) ([]byte, []span) {
+	spans := preservedSpans(text, cfg)
+	if len(spans) == 0 {
+		return text, nil
+	}
+	masked := append([]byte(nil), text...)
+	for _, s := range spans {
+		for i := s.start; i < s.end; i++ {
+			masked[i] = mask
+		}
+	}
+	return masked, spans
+}
@@ -0,0 +1,56 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/config"
+)
+
+func Test_SplitToTokens_Preserving(t *testing.T) {
+	tests := []struct {
+		name   string
+		rules  string
+		in     string
+		expect []string
+	}{
+		{"langs", "langs", "I write C++, C# and .NET code", []string{"i", "write", "c++", "c#", "and", ".net", "code"}},
+		{"langs off", "", "I write C++ code", []string{"i", "write", "c", "code"}},
+		{"versions", "versions", "Go1.22 replaced v1.21", []string{"go1.22", "replaced", "v1.21"}},
+		{"hashtags & mentions", "hashtags,mentions", "#golang tips by @rob_pike", []string{"#golang", "tips", "by", "@rob_pike"}},
+		{"email is not a mention", "mentions", "mail me@example", []string{"mail", "me", "example"}},
+		{"ids", "ids", "k8s runs 3D GPT-4 models", []string{"k8s", "runs", "3d", "gpt-4", "models"}},
+		{"words aren't ids", "ids", "plain words only", []string{"plain", "words", "only"}},
+		{"all", "all", "Go1.22 & C++ on k8s #devops", []string{"go1.22", "c++", "on", "k8s", "#devops"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.New(config.PreserveString(tt.rules))
+			assert.Equal(t, tt.expect, SplitToTokens([]byte(tt.in), cfg))
+		})
+	}
+}
+
+func Test_SplitToTokens_PreservePatterns(t *testing.T) {
+	cfg := config.New(config.PreservePatterns(`JIRA-\d+`, `ticket (\d+)`))
+	assert.Equal(t, []string{"fixed", "jira-42", "and", "ticket", "7"}, SplitToTokens([]byte("fixed JIRA-42 and ticket 7"), cfg))
+}
+
+func Test_SplitToSentencesWith(t *testing.T) {
+	cfg := config.New(config.PreserveTokens(config.Versions, config.ProgLangs))
+	sentences := SplitToSentencesWith([]byte("Go1.22 is out. Try .NET, or not!"), cfg)
+	assert.Equal(t, []string{"Go1.22 is out", "Try .NET", "or not"}, toStrings(sentences))
+
+	sentences = SplitToSentencesWith([]byte("Go1.22 is out."), config.New())
+	assert.Equal(t, []string{"Go1", "22 is out"}, toStrings(sentences))
+}
+
+func toStrings(bss [][]byte) []string {
+	res := make([]string, len(bss))
+	for i, bs := range bss {
+		res[i] = string(bs)
+	}
+	return res
+}
@@ -0,0 +1,99 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// MaxLineSize is the longest line processed at once in the streaming mode,
+// longer lines are split at the last whitespace (or just cut if there is none).
+const MaxLineSize = 64 * 1024
+
+// InputTooLargeError is returned when input exceeds the configured size.
+type InputTooLargeError struct {
+	Limit int64
+}
+
+func (e *InputTooLargeError) Error() string {
+	return fmt.Sprintf("input is larger than %d bytes", e.Limit)
+}
+
+// LimitedReader reads from the underlying reader up to the limit,
+// reading beyond the limit fails with the *InputTooLargeError.
+type LimitedReader struct {
+	io.ReadCloser
+	limit int64
+	left  int64
+	err   error
+}
+
+// LimitReader returns LimitedReader for the given reader and limit (in bytes).
+func LimitReader(r io.ReadCloser, limit int64) *LimitedReader {
+	return &LimitedReader{ReadCloser: r, limit: limit, left: limit}
+}
+
+func (r *LimitedReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	// one extra byte tells whether there is anything beyond the limit
+	if int64(len(p)) > r.left+1 {
+		p = p[:r.left+1]
+	}
+	n, err := r.ReadCloser.Read(p)
+	if int64(n) > r.left {
+		n = int(r.left)
+		r.left = 0
+		r.err = &InputTooLargeError{Limit: r.limit}
+		return n, r.err
+	}
+	r.left -= int64(n)
+	return n, err
+}
+
+// Err returns *InputTooLargeError once the limit has been exceeded.
+func (r *LimitedReader) Err() error {
+	return r.err
+}
+
+// NewLineScanner returns scanner of the lines, which keeps at most MaxLineSize bytes in memory.
+func NewLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), MaxLineSize)
+	scanner.Split(scanLines)
+	return scanner
+}
+
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, bytes.TrimSuffix(data[:i], []byte{'\r'}), nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	// too long line, split it at the last whitespace
+	if len(data) >= MaxLineSize {
+		if i := bytes.LastIndexAny(data, " \t"); i > 0 {
+			return i + 1, data[:i], nil
+		}
+		// don't cut the last rune
+		i := len(data)
+		for j := i - 1; j > 0 && j >= i-utf8.UTFMax; j-- {
+			if utf8.RuneStart(data[j]) {
+				if !utf8.FullRune(data[j:]) {
+					i = j
+				}
+				break
+			}
+		}
+		return i, data[:i], nil
+	}
+	// request more data
+	return 0, nil, nil
+}
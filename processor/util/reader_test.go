@@ -0,0 +1,47 @@
+package util
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LimitReader(t *testing.T) {
+	r := LimitReader(io.NopCloser(strings.NewReader("0123456789")), 10)
+	bs, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, "0123456789", string(bs))
+	assert.Nil(t, r.Err())
+
+	r = LimitReader(io.NopCloser(strings.NewReader("0123456789")), 5)
+	bs, err = io.ReadAll(r)
+	var tooLarge *InputTooLargeError
+	assert.True(t, errors.As(err, &tooLarge))
+	assert.Equal(t, int64(5), tooLarge.Limit)
+	assert.Equal(t, "01234", string(bs))
+	assert.Equal(t, err, r.Err())
+}
+
+func Test_NewLineScanner(t *testing.T) {
+	long := strings.Repeat("мир ", MaxLineSize/2)
+	noSpaces := strings.Repeat("мир", MaxLineSize/2)
+	scanner := NewLineScanner(strings.NewReader("first\r\n\nsecond\n" + long + "\n" + noSpaces))
+
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	assert.Nil(t, scanner.Err())
+	assert.Equal(t, []string{"first", "", "second"}, lines[:3])
+	assert.Greater(t, len(lines), 5)
+	noWS := func(s string) string { return strings.ReplaceAll(s, " ", "") }
+	assert.Equal(t, noWS(long+noSpaces), noWS(strings.Join(lines[3:], "")))
+	for _, l := range lines {
+		assert.LessOrEqual(t, len(l), MaxLineSize)
+		assert.True(t, utf8.ValidString(l))
+	}
+}
@@ -0,0 +1,71 @@
+package util
+
+import (
+	"unicode"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/model"
+)
+
+// Sections detects languages of the sections (e.g. paragraphs) of the document (see config.SectionLangs),
+// so that each section uses stop words & segmenter of its language,
+// nil Sections is safe to use and always gives configuration of the document.
+type Sections struct {
+	cfgs    map[string]*config.Config // configurations of the section languages
+	letters map[string]int            // number of letters per language
+	total   int
+}
+
+// NewSections returns sections of the document if their languages are detected, otherwise nil.
+func NewSections(cfg *config.Config) *Sections {
+	if !cfg.SectionLangs || cfg.SkipLang {
+		return nil
+	}
+	return &Sections{cfgs: map[string]*config.Config{}, letters: map[string]int{}}
+}
+
+// Config returns configuration for the section with the given text, which has the language of the section
+// or of the document, if language of the section can't be reliably detected or isn't supported.
+func (s *Sections) Config(text []byte, cfg *config.Config) *config.Config {
+	if s == nil {
+		return cfg
+	}
+	var letters int
+	for _, r := range string(text) {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if letters == 0 {
+		return cfg
+	}
+
+	lang, ok := config.DetectLangOf(string(text))
+	if !ok || !config.IsSupportedLang(lang) {
+		lang = cfg.Lang
+	}
+	s.letters[lang] += letters
+	s.total += letters
+	if lang == cfg.Lang {
+		return cfg
+	}
+
+	c, ok := s.cfgs[lang]
+	if !ok {
+		c = cfg.Clone()
+		config.SetLang(c, lang)
+		s.cfgs[lang] = c
+	}
+	return c
+}
+
+// Meta sets shares of the languages in the letters of the document.
+func (s *Sections) Meta(meta *model.Meta) {
+	if s == nil || s.total == 0 {
+		return
+	}
+	meta.Langs = make(map[string]float64, len(s.letters))
+	for lang, n := range s.letters {
+		meta.Langs[lang] = float64(n) / float64(s.total)
+	}
+}
@@ -0,0 +1,38 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/model"
+)
+
+func Test_Sections(t *testing.T) {
+	cfg := config.New()
+	config.SetLang(cfg, "en")
+	assert.Nil(t, NewSections(cfg))
+	assert.Same(t, cfg, NewSections(cfg).Config([]byte("Die neue Bibliothek ist schnell."), cfg))
+
+	cfg = config.New(config.SectionLangs(true))
+	config.SetLang(cfg, "en")
+	s := NewSections(cfg)
+
+	de := s.Config([]byte("Die neue Bibliothek macht die Verarbeitung von großen Dokumenten viel schneller als jemals zuvor."), cfg)
+	assert.Equal(t, "de", de.Lang)
+	assert.True(t, de.StopWords.IsStopWord("die"))
+	assert.Same(t, de, s.Config([]byte("Sie ist jetzt schneller als jemals zuvor und sehr einfach."), cfg))
+	assert.Equal(t, "en", cfg.Lang)
+
+	// short & unsupported sections fall back to the document language
+	assert.Same(t, cfg, s.Config([]byte("Go"), cfg))
+	assert.Same(t, cfg, s.Config([]byte("La nuova biblioteca rende l'elaborazione molto più veloce di prima."), cfg))
+	assert.Same(t, cfg, s.Config([]byte("2024"), cfg))
+
+	meta := &model.Meta{}
+	s.Meta(meta)
+	assert.Len(t, meta.Langs, 2)
+	assert.Greater(t, meta.Langs["de"], meta.Langs["en"])
+	assert.InDelta(t, 1.0, meta.Langs["de"]+meta.Langs["en"], 1e-9)
+}
@@ -6,10 +6,24 @@ import (
 	"github.com/zoomio/tagify/config"
 )
 
+// SplitToTokens splits given normalized text (see NormalizeText) into the sanitized tokens,
+// preserved tokens (see config.PreserveTokens) are only lowercased,
+// compounds are followed by their parts (see config.Decompound & TokenWeights).
 func SplitToTokens(text []byte, cfg *config.Config) []string {
+	return splitToTokens(NormalizeText(text, cfg), cfg)
+}
+
+func splitToTokens(text []byte, cfg *config.Config) []string {
 	var reg *stopwords.Register
 	if cfg.NoStopWords {
 		reg = cfg.StopWords
 	}
-	return Sanitize(cfg.Segment(text), reg)
+	d := cfg.Decompounder()
+	split := func(text []byte) []string {
+		return decompound(Sanitize(cfg.Segment(text), reg), d)
+	}
+	if cfg.PreservesTokens() {
+		return splitPreserving(text, cfg, split)
+	}
+	return split(text)
 }
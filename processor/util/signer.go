@@ -0,0 +1,15 @@
+package util
+
+import (
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/dedup"
+)
+
+// NewSigner returns signer of the document if signatures are enabled (see config.Signatures),
+// otherwise nil, which is safe to use.
+func NewSigner(cfg *config.Config) *dedup.Signer {
+	if !cfg.Signatures {
+		return nil
+	}
+	return dedup.NewSigner()
+}
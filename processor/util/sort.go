@@ -31,6 +31,30 @@ func SortTagItems(items []*model.Tag) {
 	}).Sort(items)
 }
 
+// SortTagItemsAsc sorts items by score in ascending order (e.g. for YAKE, where lower is better),
+// if scores are equal it sorts by count in descending order if counts are equal,
+// it sorts string values alphabetically.
+func SortTagItemsAsc(items []*model.Tag) {
+	by(func(i1, i2 *model.Tag) bool {
+		// Lower score goes 1st
+		if i1.Score < i2.Score {
+			return true
+		} else if i1.Score > i2.Score {
+			return false
+		}
+
+		// Bigger count goes 1st
+		if i1.Count > i2.Count {
+			return true
+		} else if i1.Count < i2.Count {
+			return false
+		}
+
+		// Alphabetic sort
+		return strings.Compare(i1.Value, i2.Value) < 0
+	}).Sort(items)
+}
+
 // ------------------------------------------ Sort ------------------------------------------
 
 // by is the type of a "less" function that defines the ordering of its item arguments.
@@ -10,11 +10,12 @@ import (
 )
 
 var (
-	sanitizeRegex              = regexp.MustCompile(`([^\p{L}-']*)([\p{L}-']+)([^\p{L}-']*)`)
-	notAWordRegex              = regexp.MustCompile(`([^\p{L}'-]+)`)
-	simpleNotAWordRegex        = regexp.MustCompile(`([^\p{L}-]+)`)
+	// marks are part of the words, e.g. vowels & tones of Thai or Burmese
+	sanitizeRegex              = regexp.MustCompile(`([^\p{L}\p{M}-']*)([\p{L}\p{M}-']+)([^\p{L}\p{M}-']*)`)
+	notAWordRegex              = regexp.MustCompile(`([^\p{L}\p{M}'-]+)`)
+	simpleNotAWordRegex        = regexp.MustCompile(`([^\p{L}\p{M}-]+)`)
 	noLetterWordRegex          = regexp.MustCompile(`[^\p{L}]`)
-	doubleNotWordySymbolsRegex = regexp.MustCompile(`[^\p{L}]{2}`)
+	doubleNotWordySymbolsRegex = regexp.MustCompile(`[^\p{L}\p{M}]{2}`)
 	punctuationRegex           = regexp.MustCompile(`[.,!?;:]+`)
 
 	newLine = []byte("\n")
@@ -24,7 +25,11 @@ var (
 
 // SplitToSentences splits given text into slice of sentences.
 func SplitToSentences(text []byte) [][]byte {
-	split := punctuationRegex.ReplaceAll(bytes.TrimSpace(text), newLine)
+	return sentencesOf(punctuationRegex.ReplaceAll(bytes.TrimSpace(text), newLine))
+}
+
+// sentencesOf splits text with the punctuation replaced by new lines.
+func sentencesOf(split []byte) [][]byte {
 	sentences := bytes.Split(split, newLine)
 	result := [][]byte{}
 	for _, v := range sentences {
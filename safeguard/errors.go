@@ -0,0 +1,52 @@
+package safeguard
+
+import (
+	"fmt"
+	"net"
+)
+
+// BlockedAddressError is returned when host resolves to a private, loopback or link-local address.
+type BlockedAddressError struct {
+	Host string
+	IP   net.IP
+}
+
+func (e *BlockedAddressError) Error() string {
+	return fmt.Sprintf("address %s of host %q is not allowed", e.IP, e.Host)
+}
+
+// SchemeNotAllowedError is returned when URL scheme is not in the list of allowed ones.
+type SchemeNotAllowedError struct {
+	Scheme string
+}
+
+func (e *SchemeNotAllowedError) Error() string {
+	return fmt.Sprintf("scheme %q is not allowed", e.Scheme)
+}
+
+// PortNotAllowedError is returned when URL port is not in the list of allowed ones.
+type PortNotAllowedError struct {
+	Port int
+}
+
+func (e *PortNotAllowedError) Error() string {
+	return fmt.Sprintf("port %d is not allowed", e.Port)
+}
+
+// ResponseTooLargeError is returned when response body exceeds the limit.
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response is larger than %d bytes", e.Limit)
+}
+
+// TooManyRedirectsError is returned when amount of redirects exceeds the limit.
+type TooManyRedirectsError struct {
+	Limit int
+}
+
+func (e *TooManyRedirectsError) Error() string {
+	return fmt.Sprintf("stopped after %d redirects", e.Limit)
+}
@@ -0,0 +1,279 @@
+package safeguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/zoomio/tagify/config"
+)
+
+const (
+	headerUserAgent = "User-Agent"
+
+	schemeFile = "file"
+
+	defaultTimeout = time.Second * 3
+)
+
+// Enabled tells whether any of the safeguards is configured.
+func Enabled(cfg *config.Config) bool {
+	return cfg.BlockPrivateNetworks ||
+		len(cfg.AllowedSchemes) > 0 ||
+		len(cfg.AllowedPorts) > 0 ||
+		cfg.MaxResponseSize > 0 ||
+		cfg.MaxRedirects != nil
+}
+
+// Guard enforces configured safeguards on the outgoing HTTP calls.
+type Guard struct {
+	blockPrivate bool
+	schemes      map[string]bool
+	ports        map[int]bool
+	maxSize      int64
+	maxRedirects int // negative means not limited
+	client       *http.Client
+}
+
+// New creates new instance of Guard based on the provided configuration.
+func New(cfg *config.Config) *Guard {
+	g := &Guard{
+		blockPrivate: cfg.BlockPrivateNetworks,
+		schemes:      map[string]bool{},
+		ports:        map[int]bool{},
+		maxSize:      cfg.MaxResponseSize,
+		maxRedirects: -1,
+	}
+	if cfg.MaxRedirects != nil {
+		g.maxRedirects = *cfg.MaxRedirects
+	}
+	for _, v := range cfg.AllowedSchemes {
+		g.schemes[strings.ToLower(v)] = true
+	}
+	for _, v := range cfg.AllowedPorts {
+		g.ports[v] = true
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// Control is called after DNS resolution, right before connecting,
+		// which prevents from bypassing checks via DNS rebinding.
+		Control: g.control,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// proxy would make dialer to connect to the proxy instead of the target
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	g.client = &http.Client{
+		Timeout:       timeout,
+		Transport:     &limitedTransport{next: transport, maxSize: g.maxSize},
+		CheckRedirect: g.checkRedirect,
+	}
+
+	return g
+}
+
+// Client returns HTTP client, which enforces the safeguards.
+func (g *Guard) Client() *http.Client {
+	return g.client
+}
+
+// Check verifies given URL before calling it, including resolution of its host,
+// handy when calls are made by something else than Client (e.g. headless browser).
+func (g *Guard) Check(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("wrong URL format %q: %w", rawURL, err)
+	}
+	if err = g.checkURL(u); err != nil {
+		return err
+	}
+	if !g.blockPrivate || !isHTTP(u) {
+		return nil
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		return g.checkIP(host, ip)
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %q: %w", host, err)
+	}
+	for _, a := range addrs {
+		if err = g.checkIP(host, a.IP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get calls given URL and returns contents of the response.
+func (g *Guard) Get(ctx context.Context, rawURL, userAgent string) ([]byte, error) {
+	if err := g.Check(ctx, rawURL); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error in creating request for source=%s: %w", rawURL, err)
+	}
+	if userAgent != "" {
+		req.Header.Set(headerUserAgent, userAgent)
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error in calling provided source=%s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status of the provided source=%s: %s", rawURL, resp.Status)
+	}
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error in reading provided source=%s: %w", rawURL, err)
+	}
+	return bs, nil
+}
+
+func (g *Guard) checkURL(u *url.URL) error {
+	scheme := strings.ToLower(u.Scheme)
+	if scheme == "" {
+		// sources without scheme are files
+		scheme = schemeFile
+	}
+	if len(g.schemes) > 0 && !g.schemes[scheme] {
+		return &SchemeNotAllowedError{Scheme: scheme}
+	}
+	if len(g.ports) > 0 && isHTTP(u) {
+		port, err := toPort(scheme, u.Port())
+		if err != nil {
+			return err
+		}
+		if !g.ports[port] {
+			return &PortNotAllowedError{Port: port}
+		}
+	}
+	return nil
+}
+
+func (g *Guard) checkIP(host string, ip net.IP) error {
+	if g.blockPrivate && isPrivate(ip) {
+		return &BlockedAddressError{Host: host, IP: ip}
+	}
+	return nil
+}
+
+func (g *Guard) control(network, address string, _ syscall.RawConn) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if len(g.ports) > 0 {
+		p, err := strconv.Atoi(port)
+		if err != nil || !g.ports[p] {
+			return &PortNotAllowedError{Port: p}
+		}
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("unexpected address %q", address)
+	}
+	return g.checkIP(host, ip)
+}
+
+func (g *Guard) checkRedirect(req *http.Request, via []*http.Request) error {
+	if g.maxRedirects >= 0 && len(via) > g.maxRedirects {
+		return &TooManyRedirectsError{Limit: g.maxRedirects}
+	}
+	// keep the default limit of the http.Client
+	if len(via) >= 10 {
+		return &TooManyRedirectsError{Limit: 10}
+	}
+	return g.checkURL(req.URL)
+}
+
+// limitedTransport fails responses, which are bigger than the limit.
+type limitedTransport struct {
+	next    http.RoundTripper
+	maxSize int64
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || t.maxSize <= 0 {
+		return resp, err
+	}
+	if resp.ContentLength > t.maxSize {
+		resp.Body.Close()
+		return nil, &ResponseTooLargeError{Limit: t.maxSize}
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, limit: t.maxSize, left: t.maxSize}
+	return resp, nil
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	limit int64
+	left  int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.left < 0 {
+		return 0, &ResponseTooLargeError{Limit: b.limit}
+	}
+	// read one byte more than allowed to find out whether the limit is exceeded
+	if int64(len(p)) > b.left+1 {
+		p = p[:b.left+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.left -= int64(n)
+	if b.left < 0 {
+		return n, &ResponseTooLargeError{Limit: b.limit}
+	}
+	return n, err
+}
+
+func isPrivate(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsUnspecified()
+}
+
+func isHTTP(u *url.URL) bool {
+	scheme := strings.ToLower(u.Scheme)
+	return scheme == "http" || scheme == "https"
+}
+
+func toPort(scheme, port string) (int, error) {
+	if port == "" {
+		switch scheme {
+		case "http":
+			return 80, nil
+		case "https":
+			return 443, nil
+		}
+		return 0, errors.New("unknown port")
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return 0, fmt.Errorf("wrong port %q: %w", port, err)
+	}
+	return p, nil
+}
@@ -0,0 +1,128 @@
+package safeguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/config"
+)
+
+var ctx = context.TODO()
+
+func newTestServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "<p>There was a Boy whose name was Jim</p>")
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, strings.Repeat("a", 1024))
+	})
+	mux.HandleFunc("/chunked", func(w http.ResponseWriter, _ *http.Request) {
+		for i := 0; i < 4; i++ {
+			fmt.Fprint(w, strings.Repeat("a", 256))
+			w.(http.Flusher).Flush()
+		}
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/redirect", http.StatusFound)
+	})
+	return httptest.NewServer(mux)
+}
+
+func Test_Enabled(t *testing.T) {
+	assert.False(t, Enabled(config.New()))
+	assert.True(t, Enabled(config.New(config.BlockPrivateNetworks(true))))
+	assert.False(t, Enabled(&config.Config{}))
+	assert.True(t, Enabled(config.New(config.MaxRedirects(0))))
+}
+
+func Test_Get(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	bs, err := New(config.New(config.MaxResponseSize(100), config.MaxRedirects(1))).Get(ctx, srv.URL, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "<p>There was a Boy whose name was Jim</p>", string(bs))
+}
+
+func Test_BlockPrivateNetworks(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	g := New(config.New(config.BlockPrivateNetworks(true)))
+
+	var blocked *BlockedAddressError
+	err := g.Check(ctx, srv.URL)
+	assert.True(t, errors.As(err, &blocked))
+	assert.True(t, blocked.IP.IsLoopback())
+
+	// client checks resolved address on its own
+	_, err = g.Client().Get(srv.URL)
+	assert.True(t, errors.As(err, &blocked))
+
+	err = g.Check(ctx, "http://169.254.169.254/latest/meta-data")
+	assert.True(t, errors.As(err, &blocked))
+}
+
+func Test_AllowedSchemes(t *testing.T) {
+	g := New(config.New(config.AllowedSchemes([]string{"https"})))
+
+	var scheme *SchemeNotAllowedError
+	assert.True(t, errors.As(g.Check(ctx, "ftp://example.com/file"), &scheme))
+	assert.Equal(t, "ftp", scheme.Scheme)
+	assert.True(t, errors.As(g.Check(ctx, "/etc/passwd"), &scheme))
+	assert.Equal(t, "file", scheme.Scheme)
+	assert.Nil(t, g.Check(ctx, "https://example.com"))
+}
+
+func Test_AllowedPorts(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	g := New(config.New(config.AllowedPorts([]int{80, 443})))
+
+	var port *PortNotAllowedError
+	assert.True(t, errors.As(g.Check(ctx, "http://example.com:8080"), &port))
+	assert.Equal(t, 8080, port.Port)
+	assert.Nil(t, g.Check(ctx, "http://example.com"))
+	assert.Nil(t, g.Check(ctx, "https://example.com:443"))
+
+	_, err := g.Get(ctx, srv.URL, "")
+	assert.True(t, errors.As(err, &port))
+}
+
+func Test_MaxResponseSize(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	g := New(config.New(config.MaxResponseSize(512)))
+
+	var tooLarge *ResponseTooLargeError
+	_, err := g.Get(ctx, srv.URL+"/large", "")
+	assert.True(t, errors.As(err, &tooLarge))
+	assert.Equal(t, int64(512), tooLarge.Limit)
+
+	_, err = g.Get(ctx, srv.URL+"/chunked", "")
+	assert.True(t, errors.As(err, &tooLarge))
+}
+
+func Test_MaxRedirects(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	var redirects *TooManyRedirectsError
+	_, err := New(config.New(config.MaxRedirects(2))).Get(ctx, srv.URL+"/redirect", "")
+	assert.True(t, errors.As(err, &redirects))
+	assert.Equal(t, 2, redirects.Limit)
+
+	_, err = New(config.New(config.MaxRedirects(0))).Get(ctx, srv.URL+"/redirect", "")
+	assert.True(t, errors.As(err, &redirects))
+	assert.Equal(t, 0, redirects.Limit)
+}
@@ -0,0 +1,149 @@
+package similarity
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/zoomio/tagify/internal/fsutil"
+	"github.com/zoomio/tagify/model"
+)
+
+// Match is a document found in the index.
+type Match struct {
+	ID    string
+	Score float64 // cosine similarity
+}
+
+// Index keeps vectors of the documents in memory and finds related ones,
+// it is safe for concurrent use.
+type Index struct {
+	mu   sync.RWMutex
+	docs map[string]Vector // normalized vectors
+}
+
+// NewIndex creates empty index.
+func NewIndex() *Index {
+	return &Index{docs: map[string]Vector{}}
+}
+
+// Add puts (or replaces) document with the given ID into the index.
+func (idx *Index) Add(id string, res *model.Result) {
+	idx.AddVector(id, NewVector(res))
+}
+
+// AddVector puts (or replaces) vector of the document with the given ID into the index.
+func (idx *Index) AddVector(id string, v Vector) {
+	n := v.Normalize()
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.docs[id] = n
+}
+
+// Remove deletes document with the given ID from the index.
+func (idx *Index) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	delete(idx.docs, id)
+}
+
+// Len returns number of the documents in the index.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Related returns up to k documents most similar to the given result,
+// documents without common tags are never returned.
+func (idx *Index) Related(res *model.Result, k int) []Match {
+	return idx.RelatedVector(NewVector(res), k)
+}
+
+// RelatedVector returns up to k documents most similar to the given vector.
+func (idx *Index) RelatedVector(v Vector, k int) []Match {
+	return idx.related(v.Normalize(), k, "")
+}
+
+// RelatedTo returns up to k documents most similar to the indexed document with the given ID,
+// the document itself is excluded, nil is returned if there is no such document.
+func (idx *Index) RelatedTo(id string, k int) []Match {
+	idx.mu.RLock()
+	v, ok := idx.docs[id]
+	idx.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return idx.related(v, k, id)
+}
+
+func (idx *Index) related(v Vector, k int, skip string) []Match {
+	if k <= 0 || len(v) == 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	matches := make([]Match, 0, len(idx.docs))
+	for id, doc := range idx.docs {
+		if id == skip {
+			continue
+		}
+		if score := v.Dot(doc); score > 0 {
+			matches = append(matches, Match{ID: id, Score: score})
+		}
+	}
+	idx.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches
+}
+
+// Save stores the index in the given file as JSON.
+func (idx *Index) Save(path string) error {
+	idx.mu.RLock()
+	bs, err := json.Marshal(idx.docs)
+	idx.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode index: %w", err)
+	}
+
+	if err = fsutil.WriteFile(path, bs); err != nil {
+		return fmt.Errorf("failed to store index %q: %w", path, err)
+	}
+	return nil
+}
+
+// LoadIndex reads index stored with Index.Save, empty index is returned if file doesn't exist or is empty.
+func LoadIndex(path string) (*Index, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return NewIndex(), nil
+		}
+		return nil, fmt.Errorf("failed to read index %q: %w", path, err)
+	}
+	idx := NewIndex()
+	if len(bytes.TrimSpace(bs)) == 0 {
+		return idx, nil
+	}
+	if err = json.Unmarshal(bs, &idx.docs); err != nil {
+		return nil, fmt.Errorf("failed to decode index %q: %w", path, err)
+	}
+	// "null" leaves documents unset
+	if idx.docs == nil {
+		idx.docs = map[string]Vector{}
+	}
+	return idx, nil
+}
@@ -0,0 +1,130 @@
+package similarity
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/internal/testutil"
+	"github.com/zoomio/tagify/model"
+)
+
+var (
+	chocolate = testutil.NewResult(map[string]float64{"chocolate": 3, "cocoa": 2, "sweet": 1})
+	cocoa     = testutil.NewResult(map[string]float64{"cocoa": 3, "beans": 2, "chocolate": 1})
+	golang    = testutil.NewResult(map[string]float64{"go": 3, "concurrency": 2, "channels": 1})
+)
+
+func Test_NewVector(t *testing.T) {
+	assert.Equal(t, Vector{"chocolate": 3, "cocoa": 2, "sweet": 1}, NewVector(chocolate))
+
+	// ranked tags take precedence
+	res := testutil.NewResult(map[string]float64{"chocolate": 3, "cocoa": 2})
+	res.Tags = []*model.Tag{{Value: "chocolate", Score: 1}}
+	assert.Equal(t, Vector{"chocolate": 1}, NewVector(res))
+
+	assert.Empty(t, NewVector(nil))
+	assert.Empty(t, NewVector(model.EmptyResult()))
+}
+
+var cosineTests = []struct {
+	name   string
+	a, b   Vector
+	expect float64
+}{
+	{"same", Vector{"a": 1, "b": 2}, Vector{"a": 1, "b": 2}, 1},
+	{"proportional", Vector{"a": 1, "b": 2}, Vector{"a": 2, "b": 4}, 1},
+	{"disjoint", Vector{"a": 1}, Vector{"b": 1}, 0},
+	{"partial", Vector{"a": 1, "b": 1}, Vector{"a": 1}, 0.7071},
+	{"empty", Vector{}, Vector{"a": 1}, 0},
+}
+
+func Test_Cosine(t *testing.T) {
+	for _, tt := range cosineTests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.expect, Cosine(tt.a, tt.b), 0.0001)
+			assert.InDelta(t, tt.expect, Cosine(tt.b, tt.a), 0.0001)
+		})
+	}
+	assert.InDelta(t, 1, Similarity(chocolate, chocolate), 0.0001)
+	assert.Greater(t, Similarity(chocolate, cocoa), Similarity(chocolate, golang))
+}
+
+func Test_Index_Related(t *testing.T) {
+	idx := NewIndex()
+	idx.Add("chocolate", chocolate)
+	idx.Add("cocoa", cocoa)
+	idx.Add("golang", golang)
+	assert.Equal(t, 3, idx.Len())
+
+	query := testutil.NewResult(map[string]float64{"chocolate": 2, "sweet": 2})
+	matches := idx.Related(query, 5)
+	assert.Len(t, matches, 2)
+	assert.Equal(t, "chocolate", matches[0].ID)
+	assert.Equal(t, "cocoa", matches[1].ID)
+	assert.Len(t, idx.Related(query, 1), 1)
+
+	matches = idx.RelatedTo("chocolate", 5)
+	assert.Equal(t, []string{"cocoa"}, ids(matches))
+	assert.Nil(t, idx.RelatedTo("missing", 5))
+
+	idx.Remove("cocoa")
+	assert.Empty(t, idx.RelatedTo("chocolate", 5))
+}
+
+func Test_Index_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	idx, err := LoadIndex(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, idx.Len())
+
+	idx.Add("chocolate", chocolate)
+	idx.Add("cocoa", cocoa)
+	assert.Nil(t, idx.Save(path))
+
+	loaded, err := LoadIndex(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, loaded.Len())
+	assert.Equal(t, idx.RelatedTo("chocolate", 1), loaded.RelatedTo("chocolate", 1))
+}
+
+func Test_Index_LoadEmpty(t *testing.T) {
+	for _, content := range []string{"null", "", " \n"} {
+		path := filepath.Join(t.TempDir(), "index.json")
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+
+		idx, err := LoadIndex(path)
+		assert.Nil(t, err, content)
+		assert.Equal(t, 0, idx.Len(), content)
+
+		idx.Add("chocolate", chocolate)
+		assert.Equal(t, 1, idx.Len(), content)
+	}
+}
+
+func Test_Index_Concurrent(t *testing.T) {
+	idx := NewIndex()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			idx.Add(string(rune('a'+i)), chocolate)
+			idx.Related(cocoa, 3)
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 10, idx.Len())
+}
+
+func ids(matches []Match) []string {
+	out := make([]string, len(matches))
+	for i, m := range matches {
+		out[i] = m.ID
+	}
+	return out
+}
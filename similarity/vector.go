@@ -0,0 +1,98 @@
+// Package similarity compares documents by their tags.
+//
+// Result of Tagify is turned into a sparse Vector of the tag scores,
+// vectors are compared with the cosine similarity, Index finds
+// the most related documents to the given one.
+package similarity
+
+import (
+	"math"
+
+	"github.com/zoomio/tagify/model"
+)
+
+// Vector is a sparse vector of the tag scores, keyed by tag values.
+type Vector map[string]float64
+
+// NewVector turns result into the vector, ranked tags (Result.Tags) are used
+// if there are any, otherwise all the found tags (Result.RawTags).
+func NewVector(res *model.Result) Vector {
+	if res == nil {
+		return Vector{}
+	}
+	tags := res.Tags
+	if len(tags) == 0 {
+		tags = res.Flatten()
+	}
+	return vectorOf(tags)
+}
+
+// NewRawVector turns all the found tags (Result.RawTags) of the result into the vector,
+// regardless of the limit of the ranked tags.
+func NewRawVector(res *model.Result) Vector {
+	if res == nil {
+		return Vector{}
+	}
+	return vectorOf(res.Flatten())
+}
+
+func vectorOf(tags []*model.Tag) Vector {
+	v := make(Vector, len(tags))
+	for _, t := range tags {
+		if t.Score > 0 {
+			v[t.Value] += t.Score
+		}
+	}
+	return v
+}
+
+// Norm returns Euclidean length of the vector.
+func (v Vector) Norm() float64 {
+	var sum float64
+	for _, w := range v {
+		sum += w * w
+	}
+	return math.Sqrt(sum)
+}
+
+// Normalize returns copy of the vector of unit length,
+// the cosine similarity of the normalized vectors is just their dot product.
+func (v Vector) Normalize() Vector {
+	n := v.Norm()
+	out := make(Vector, len(v))
+	if n == 0 {
+		return out
+	}
+	for k, w := range v {
+		out[k] = w / n
+	}
+	return out
+}
+
+// Dot returns dot product of the vectors.
+func (v Vector) Dot(o Vector) float64 {
+	// iterate over the smaller one
+	if len(o) < len(v) {
+		v, o = o, v
+	}
+	var sum float64
+	for k, w := range v {
+		sum += w * o[k]
+	}
+	return sum
+}
+
+// Cosine returns cosine similarity of the vectors, which is 0 for vectors without
+// common tags and 1 for vectors with the same proportions of the same tags.
+func Cosine(a, b Vector) float64 {
+	na, nb := a.Norm(), b.Norm()
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return a.Dot(b) / (na * nb)
+}
+
+// Similarity returns cosine similarity of the results (see NewVector).
+func Similarity(a, b *model.Result) float64 {
+	return Cosine(NewVector(a), NewVector(b))
+}
@@ -0,0 +1,54 @@
+package tagify
+
+import (
+	"context"
+
+	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/model"
+)
+
+// Tagger produces tags for many sources with the same configuration.
+// Configuration is resolved once (tag weights are read, stop words & dictionaries are loaded),
+// every call to Tag works with its own copy of it, hence Tagger is safe for concurrent use,
+// as long as provided extensions are.
+type Tagger struct {
+	cfg *config.Config
+	// stop words of the Tagger are defined by its language
+	langStopWords bool
+}
+
+// NewTagger creates new instance of Tagger with the given options,
+// invalid configuration is reported with the *config.ValidationError.
+func NewTagger(options ...Option) (*Tagger, error) {
+	cfg, err := config.NewWithError(options...)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tagger{cfg: cfg}
+	if t.cfg.Lang != "" && t.cfg.StopWords == nil {
+		config.SetLang(t.cfg, t.cfg.Lang)
+		t.langStopWords = true
+	}
+	if t.cfg.Lang == "" || config.NeedsDict(t.cfg.Lang) {
+		config.LoadDict()
+	}
+	return t, nil
+}
+
+// Tag produces slice of tags ordered by frequency for the given source,
+// given options override the options of the Tagger for this call only.
+func (t *Tagger) Tag(ctx context.Context, source string, options ...Option) (*model.Result, error) {
+	cfg := t.cfg.Clone()
+	cfg.Source = source
+	for _, option := range options {
+		option(cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	// stop words are set up for the overridden language during processing
+	if t.langStopWords && cfg.Lang != t.cfg.Lang && cfg.StopWords == t.cfg.StopWords {
+		cfg.StopWords = nil
+	}
+	return run(ctx, cfg)
+}
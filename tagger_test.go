@@ -0,0 +1,105 @@
+package tagify
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/zoomio/tagify/config"
+)
+
+const taggerMD = `# Chocolate
+
+Chocolate is made of cocoa beans, chocolate is sweet.
+`
+
+func newTaggerServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.md":
+			w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+			fmt.Fprint(w, taggerMD)
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, indexHTML)
+		}
+	}))
+}
+
+func Test_Tagger_Tag(t *testing.T) {
+	srv := newTaggerServer()
+	defer srv.Close()
+
+	tagger, err := NewTagger(Limit(5), NoStopWords(true), Language("en"))
+	assert.Nil(t, err)
+
+	expected, err := Run(ctx, Source(srv.URL), Limit(5), NoStopWords(true), Language("en"))
+	assert.Nil(t, err)
+
+	res, err := tagger.Tag(ctx, srv.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, expected.TagsStrings(), res.TagsStrings())
+
+	// per-call override
+	res, err = tagger.Tag(ctx, srv.URL, Limit(1))
+	assert.Nil(t, err)
+	assert.Len(t, res.Tags, 1)
+}
+
+func Test_Tagger_ExtraTagWeights_NoLeak(t *testing.T) {
+	srv := newTaggerServer()
+	defer srv.Close()
+
+	tagger, err := NewTagger(Limit(3), NoStopWords(true), Language("en"))
+	assert.Nil(t, err)
+
+	before, err := tagger.Tag(ctx, srv.URL)
+	assert.Nil(t, err)
+
+	boosted, err := tagger.Tag(ctx, srv.URL, ExtraTagWeightsString("title:100"))
+	assert.Nil(t, err)
+	assert.Equal(t, "test", boosted.Tags[0].Value)
+
+	after, err := tagger.Tag(ctx, srv.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, before.TagsStrings(), after.TagsStrings())
+}
+
+func Test_Tagger_Concurrent(t *testing.T) {
+	srv := newTaggerServer()
+	defer srv.Close()
+
+	tagger, err := NewTagger(Limit(5), NoStopWords(true))
+	assert.Nil(t, err)
+
+	expectedHTML, err := tagger.Tag(ctx, srv.URL)
+	assert.Nil(t, err)
+	expectedMD, err := tagger.Tag(ctx, srv.URL+"/index.md")
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			switch i % 3 {
+			case 0:
+				res, err := tagger.Tag(ctx, srv.URL)
+				assert.Nil(t, err)
+				assert.Equal(t, expectedHTML.TagsStrings(), res.TagsStrings())
+			case 1:
+				res, err := tagger.Tag(ctx, srv.URL+"/index.md")
+				assert.Nil(t, err)
+				assert.Equal(t, expectedMD.TagsStrings(), res.TagsStrings())
+			default:
+				_, err := tagger.Tag(ctx, srv.URL, ExtraTagWeightsString("title:100"), TargetType(config.HTML))
+				assert.Nil(t, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
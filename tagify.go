@@ -3,19 +3,31 @@ package tagify
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/extension"
 	"github.com/zoomio/tagify/model"
 	"github.com/zoomio/tagify/processor"
 	"github.com/zoomio/tagify/processor/html"
 	"github.com/zoomio/tagify/processor/md"
 	"github.com/zoomio/tagify/processor/text"
+	"github.com/zoomio/tagify/processor/util"
 )
 
-// Run produces slice of tags ordered by frequency.
+// Run produces slice of tags ordered by frequency,
+// invalid configuration is reported with the *config.ValidationError.
 func Run(ctx context.Context, options ...Option) (*model.Result, error) {
+	cfg, err := config.NewWithError(options...)
+	if err != nil {
+		return nil, err
+	}
+	return run(ctx, cfg)
+}
 
-	cfg := config.New(options...)
+func run(ctx context.Context, cfg *config.Config) (*model.Result, error) {
+	// results of the extensions are collected per run
+	ctx = extension.WithRun(ctx)
 
 	var in in
 	var err error
@@ -33,32 +45,73 @@ func Run(ctx context.Context, options ...Option) (*model.Result, error) {
 		return nil, err
 	}
 
-	res := processInput(&in, cfg)
+	if res := in.cachedResult(""); res != nil {
+		if cfg.Verbose {
+			fmt.Printf("using cached result of %s\n", cfg.Source)
+		}
+		return res, nil
+	}
+
+	res, err := processInput(ctx, &in, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// contents are the same, although they have been fetched again
+	if cached := in.cachedResult(res.Meta.DocHash); cached != nil {
+		if cfg.Verbose {
+			fmt.Printf("contents of %s have not changed, using cached result\n", cfg.Source)
+		}
+		return cached, nil
+	}
 
 	if len(res.RawTags) > 0 {
 		if cfg.Verbose {
 			fmt.Println("tagifying...")
 		}
-		res.Tags = processor.Run(cfg, res.Flatten())
+		res.Tags = processor.Run(ctx, cfg, res.Flatten())
 		if cfg.Verbose {
 			fmt.Printf("\n%v\n", res.Tags)
 		}
 	}
 
+	if len(cfg.Extensions) > 0 {
+		res.Extensions = extension.RunResults(ctx, cfg.Extensions)
+	}
+
+	if err = in.storeResult(res); err != nil && cfg.Verbose {
+		fmt.Printf("failed to cache result: %v\n", err)
+	}
+
 	return res, nil
 }
 
-func processInput(in *in, c *Config) *model.Result {
+func processInput(ctx context.Context, in *in, c *Config) (*model.Result, error) {
+	var r io.ReadCloser = in
+	var limited *util.LimitedReader
+	if c.MaxInputSize > 0 {
+		limited = util.LimitReader(in, c.MaxInputSize)
+		r = limited
+	}
+
+	var res *model.Result
 	switch in.ContentType {
 	case HTML:
-		res := html.ProcessHTML(c, in)
+		res = html.ProcessHTML(ctx, c, r)
 		if c.Screenshot && len(in.reader.ImgBytes) > 0 {
 			res.Meta.Screenshot = in.reader.ImgBytes
 		}
-		return res
 	case Markdown:
-		return md.ProcessMD(c, in)
+		res = md.ProcessMD(ctx, c, r)
 	default:
-		return text.ProcessText(c, in)
+		res = text.ProcessText(ctx, c, r)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if limited != nil && limited.Err() != nil {
+		return nil, limited.Err()
 	}
+	return res, nil
 }
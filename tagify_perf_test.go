@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -31,3 +33,38 @@ func BenchmarkTagify(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkTagify_Text(b *testing.B) {
+	benchmarkText(b, false)
+}
+
+func BenchmarkTagify_TextStream(b *testing.B) {
+	benchmarkText(b, true)
+}
+
+func benchmarkText(b *testing.B, stream bool) {
+	path := filepath.Join(b.TempDir(), "transcript.txt")
+	line := "There was a boy whose name was Jim. His friends were very good to him.\n"
+	if err := os.WriteFile(path, []byte(strings.Repeat(line, 20000)), 0644); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	ctx := context.TODO()
+
+	for i := 0; i < b.N; i++ {
+		_, err := Run(ctx,
+			Source(path),
+			TargetType(Text),
+			Stream(stream),
+			Limit(40),
+			NoStopWords(true),
+		)
+		if err != nil {
+			b.Fatal(err)
+			break
+		}
+	}
+}
@@ -2,19 +2,27 @@ package tagify
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/net/html"
 
+	"github.com/zoomio/tagify/cache"
 	"github.com/zoomio/tagify/config"
+	"github.com/zoomio/tagify/dedup"
 	"github.com/zoomio/tagify/extension"
 	"github.com/zoomio/tagify/model"
 	thtml "github.com/zoomio/tagify/processor/html"
+	"github.com/zoomio/tagify/processor/util"
+	"github.com/zoomio/tagify/safeguard"
 )
 
 var ctx = context.TODO()
@@ -121,6 +129,331 @@ func Test_CustomHTML(t *testing.T) {
 	assert.Equal(t, 2, found) */
 }
 
+func Test_Run_Cache(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		hits++
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, indexHTML)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	opts := []Option{Source(srv.URL), Cache(dir), Limit(5), NoStopWords(true)}
+
+	res1, err := Run(ctx, opts...)
+	assert.Nil(t, err)
+
+	// cached contents are replaced, so that processing of them would give another result
+	c := cache.New(dir, nil)
+	e, err := c.Get(srv.URL)
+	assert.Nil(t, err)
+	e.Body = []byte("<html><head><title>Other</title></head><body><p>zebra zebra</p></body></html>")
+	assert.Nil(t, c.Put(e))
+
+	// unchanged page isn't processed again
+	res2, err := Run(ctx, opts...)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, res1.Meta.DocHash, res2.Meta.DocHash)
+	assert.Equal(t, res1.Meta.DocTitle, res2.Meta.DocTitle)
+	assert.Equal(t, res1.TagsStrings(), res2.TagsStrings())
+
+	// cached contents are processed with the other options
+	res3, err := Run(ctx, append(opts, Signatures(true))...)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, "Other", res3.Meta.DocTitle)
+	assert.Equal(t, []string{"zebra"}, res3.TagsStrings())
+	assert.NotEmpty(t, res3.Meta.MinHash)
+}
+
+func Test_Run_Cache_DocHash(t *testing.T) {
+	var hits int
+	// no validators, hence page is always fetched
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, indexHTML)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	opts := []Option{Source(srv.URL), Cache(dir), Limit(5), NoStopWords(true)}
+
+	_, err := Run(ctx, opts...)
+	assert.Nil(t, err)
+
+	// cached result is replaced, so that it is distinguishable from the ranked one
+	c := cache.New(dir, nil)
+	e, err := c.Get(srv.URL)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, e.DocHash)
+	e.Tags = []*model.Tag{{Value: "cached", Score: 1}}
+	assert.Nil(t, c.Put(e))
+
+	// contents with the same hash aren't ranked again
+	res, err := Run(ctx, opts...)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, hits)
+	assert.Equal(t, []string{"cached"}, res.TagsStrings())
+
+	// but they are with the other options
+	res, err = Run(ctx, append(opts, Limit(1))...)
+	assert.Nil(t, err)
+	assert.Len(t, res.Tags, 1)
+	assert.NotEqual(t, []string{"cached"}, res.TagsStrings())
+}
+
+func Test_Run_BlockPrivateNetworks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, indexHTML)
+	}))
+	defer srv.Close()
+
+	_, err := Run(ctx, Source(srv.URL), BlockPrivateNetworks(true))
+	var blocked *safeguard.BlockedAddressError
+	assert.True(t, errors.As(err, &blocked))
+
+	res, err := Run(ctx, Source(srv.URL), MaxResponseSize(int64(len(indexHTML))), Limit(1))
+	assert.Nil(t, err)
+	assert.Equal(t, "Test", res.Meta.DocTitle)
+}
+
+func Test_Run_InvalidConfig(t *testing.T) {
+	_, err := Run(ctx, Content("foo bar"), TagWeightsString("h1"), Language("xx"), Limit(-1))
+	var cfgErr *config.ValidationError
+	assert.True(t, errors.As(err, &cfgErr))
+	assert.Len(t, cfgErr.Errs, 3)
+}
+
+func Test_Run_Cancel(t *testing.T) {
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := Run(cancelled, Content(strings.Repeat("There was a boy whose name was Jim.\n", 100000)), TargetType(Text))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// endless site, every page links to the new ones
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, "<html><body><p>Page %s is about chocolate.</p>", r.URL.Path)
+		for i := 0; i < 5; i++ {
+			fmt.Fprintf(w, "<a href=\"%s/%d\">%d</a>", strings.TrimSuffix(r.URL.Path, "/"), i, i)
+		}
+		fmt.Fprint(w, "</body></html>")
+	}))
+	defer srv.Close()
+
+	timed, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = Run(timed, Source(srv.URL), FullSite(true))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func Test_Run_MaxInputSize(t *testing.T) {
+	doc := strings.Repeat("There was a boy whose name was Jim.\n", 1000)
+
+	for _, contentType := range []ContentType{Text, Markdown, HTML} {
+		for _, stream := range []bool{false, true} {
+			_, err := Run(ctx, Content(doc), TargetType(contentType), Stream(stream), MaxInputSize(100))
+			var tooLarge *util.InputTooLargeError
+			assert.True(t, errors.As(err, &tooLarge), "%s, stream: %t", contentType, stream)
+		}
+	}
+
+	res, err := Run(ctx, Content(doc), TargetType(Text), Stream(true), MaxInputSize(int64(len(doc))), NoStopWords(true), Limit(1))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"boy"}, res.TagsStrings())
+}
+
+func Test_Run_Signatures(t *testing.T) {
+	article := "The central bank raised interest rates by a quarter point on Wednesday. " +
+		"It cited persistent inflation in services and a tight labour market. " +
+		"Further increases would depend on incoming data about wages, prices and growth."
+
+	res, err := Run(ctx, Content(article), TargetType(Text))
+	assert.Nil(t, err)
+	assert.Empty(t, res.Meta.MinHash)
+
+	var metas []*model.Meta
+	for _, doc := range []struct {
+		contentType ContentType
+		content     string
+	}{
+		{Text, article},
+		{Text, "Read more news. " + article + " Subscribe to our newsletter."},
+		{HTML, "<html><body><p>" + article + "</p></body></html>"},
+		{Markdown, "# News\n\n" + article},
+	} {
+		res, err := Run(ctx, Content(doc.content), TargetType(doc.contentType), Signatures(true))
+		assert.Nil(t, err)
+		assert.Len(t, res.Meta.MinHash, dedup.MinHashSize)
+		metas = append(metas, res.Meta)
+	}
+	for _, meta := range metas[1:] {
+		assert.Greater(t, dedup.Similarity(metas[0], meta), 0.6)
+	}
+}
+
+func Test_Run_Keywords(t *testing.T) {
+	doc := "Tagify extracts keywords from web pages. Keyword extraction with Tagify is fast. " +
+		"Statistical keyword extraction works on short texts."
+
+	for _, tt := range []struct {
+		contentType ContentType
+		content     string
+	}{
+		{Text, doc},
+		{HTML, "<html><body><p>" + doc + "</p></body></html>"},
+		{Markdown, doc},
+	} {
+		res, err := Run(ctx, Content(tt.content), TargetType(tt.contentType), AlgorithmString("rake"), Limit(3))
+		assert.Nil(t, err)
+		assert.Len(t, res.Tags, 3)
+		assert.Contains(t, res.TagsStrings(), "keyword extraction")
+		for i := 1; i < len(res.Tags); i++ {
+			assert.GreaterOrEqual(t, res.Tags[i-1].Score, res.Tags[i].Score)
+		}
+
+		res, err = Run(ctx, Content(tt.content), TargetType(tt.contentType), KeywordAlgorithm(YAKE), Limit(3))
+		assert.Nil(t, err)
+		assert.Len(t, res.Tags, 3)
+		for i := 1; i < len(res.Tags); i++ {
+			assert.LessOrEqual(t, res.Tags[i-1].Score, res.Tags[i].Score)
+		}
+	}
+}
+
+func Test_Run_Diversify(t *testing.T) {
+	doc := "Kubernetes kubernetes cluster cluster. Python notebooks. Rust crates. Go modules."
+
+	res, err := Run(ctx, Content(doc), TargetType(Text), Limit(2))
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"kubernetes", "cluster"}, res.TagsStrings())
+
+	// "cluster" always co-occurs with "kubernetes", hence gives its slot away
+	res, err = Run(ctx, Content(doc), TargetType(Text), Limit(2), Diversify(0.3))
+	assert.Nil(t, err)
+	assert.Len(t, res.Tags, 2)
+	assert.Contains(t, []string{"kubernetes", "cluster"}, res.Tags[0].Value)
+	assert.NotContains(t, []string{"kubernetes", "cluster"}, res.Tags[1].Value)
+}
+
+func Test_Run_Entities(t *testing.T) {
+	doc := "Yesterday Tim Cook presented new phones. Investors of Apple were happy. " +
+		"Everyone got an apple. Analysts at NASA watched the show."
+
+	res, err := Run(ctx, Content(doc), TargetType(Text), NoStopWords(true), Entities(true), Limit(20))
+	assert.Nil(t, err)
+
+	entities := map[string]model.EntityKind{}
+	for _, tag := range res.Tags {
+		if tag.Entity != model.NoEntity {
+			entities[tag.Value] = tag.Entity
+		}
+	}
+	assert.Equal(t, map[string]model.EntityKind{"Tim Cook": model.Name, "Apple": model.Name, "NASA": model.Acronym}, entities)
+	assert.Contains(t, res.TagsStrings(), "apple")
+}
+
+func Test_Run_PreserveTokens(t *testing.T) {
+	doc := "<html><body><h1>What is new in Go1.22</h1><p>Go1.22 ships faster builds. " +
+		"Compared to C++ builds, Go1.22 builds are fast. #golang</p></body></html>"
+
+	res, err := Run(ctx, Content(doc), TargetType(HTML), NoStopWords(true), PreserveString("all"), Limit(10))
+	assert.Nil(t, err)
+	assert.Subset(t, res.TagsStrings(), []string{"go1.22", "c++", "#golang"})
+	assert.NotContains(t, res.TagsStrings(), "go")
+}
+
+func Test_Run_Thai(t *testing.T) {
+	doc := "<html><body><h1>นักท่องเที่ยวในกรุงเทพ</h1><p>นักท่องเที่ยวชอบกรุงเทพ วันนี้นักท่องเที่ยวกินข้าวที่กรุงเทพ</p></body></html>"
+
+	res, err := Run(ctx, Content(doc), TargetType(HTML), NoStopWords(true), Limit(10))
+	assert.Nil(t, err)
+	assert.Equal(t, "th", res.Meta.Lang)
+	assert.ElementsMatch(t, []string{"นักท่องเที่ยว", "กรุงเทพ", "ชอบ", "วันนี้", "กิน", "ข้าว"}, res.TagsStrings())
+	assert.NotContains(t, res.TagsStrings(), "ที่")
+}
+
+func Test_Run_Decompound(t *testing.T) {
+	doc := "Die Datenschutzgrundverordnung gilt seit Mai. Die Datenschutzbehörden prüfen die Einhaltung. " +
+		"Jedes Unternehmen braucht einen Beauftragten."
+
+	res, err := Run(ctx, Content(doc), TargetType(Text), Language("de"), NoStopWords(true), Limit(20))
+	assert.Nil(t, err)
+	assert.NotContains(t, res.TagsStrings(), "datenschutz")
+
+	res, err = Run(ctx, Content(doc), TargetType(Text), Language("de"), NoStopWords(true), Decompound(0.5), Limit(20))
+	assert.Nil(t, err)
+	assert.Subset(t, res.TagsStrings(), []string{"datenschutzgrundverordnung", "datenschutz", "verordnung", "behörde"})
+
+	doc = "De gemeenteraadsverkiezingen zijn in maart. De gemeenteraad telt de stemmen. " +
+		"Veel kiezers hebben een mening over de verkiezing."
+	res, err = Run(ctx, Content(doc), TargetType(Text), Language("nl"), NoStopWords(true), Decompound(0.5), Limit(20))
+	assert.Nil(t, err)
+	assert.Subset(t, res.TagsStrings(), []string{"gemeenteraadsverkiezingen", "gemeenteraad", "verkiezing"})
+	assert.NotContains(t, res.TagsStrings(), "de")
+}
+
+func Test_Run_Normalize(t *testing.T) {
+	doc := "Ёлка стоит в зале. Елка очень красивая. Новая ёлка куплена."
+
+	res, err := Run(ctx, Content(doc), TargetType(Text), NoStopWords(true), Limit(20))
+	assert.Nil(t, err)
+	assert.Contains(t, res.TagsStrings(), "елка")
+	assert.NotContains(t, res.TagsStrings(), "ёлка")
+
+	res, err = Run(ctx, Content("الكِتَابُ جميل. الكتاب مفيد. الكـــتاب قديم."), TargetType(Text), NoStopWords(true), Limit(20))
+	assert.Nil(t, err)
+	assert.Contains(t, res.TagsStrings(), "الكتاب")
+	assert.Len(t, res.TagsStrings(), 4)
+}
+
+func Test_Run_SectionLangs(t *testing.T) {
+	doc := "<html><body>" +
+		"<p>The new library makes the processing of large documents much faster than ever before.</p>" +
+		"<p>Die neue Bibliothek macht die Verarbeitung von großen Dokumenten viel schneller als jemals zuvor.</p>" +
+		"</body></html>"
+
+	res, err := Run(ctx, Content(doc), TargetType(HTML), NoStopWords(true), Limit(30))
+	assert.Nil(t, err)
+	assert.Equal(t, "de", res.Meta.Lang)
+	assert.Subset(t, res.TagsStrings(), []string{"the", "of"})
+	assert.Nil(t, res.Meta.Langs)
+
+	for _, contentType := range []ContentType{HTML, Text, Markdown} {
+		content := doc
+		if contentType != HTML {
+			content = strings.NewReplacer("<html><body>", "", "</body></html>", "", "<p>", "", "</p>", "\n\n").Replace(doc)
+		}
+		res, err = Run(ctx, Content(content), TargetType(contentType), NoStopWords(true), SectionLangs(true), Limit(30))
+		assert.Nil(t, err)
+		assert.Subset(t, res.TagsStrings(), []string{"library", "bibliothek", "verarbeitung"}, "%s", contentType)
+		assert.NotContains(t, res.TagsStrings(), "the", "%s", contentType)
+		assert.NotContains(t, res.TagsStrings(), "die", "%s", contentType)
+		assert.ElementsMatch(t, []string{"en", "de"}, keys(res.Meta.Langs), "%s", contentType)
+		assert.InDelta(t, 1.0, res.Meta.Langs["en"]+res.Meta.Langs["de"], 1e-9)
+	}
+}
+
+func keys(m map[string]float64) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	return ks
+}
+
 // startServer is a simple HTTP server that displays the passed headers in the html.
 func startServer(addr string, pageHTML string) *http.Server {
 	mux := http.NewServeMux()